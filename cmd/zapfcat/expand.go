@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/adzil/zapf/zapproto"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	typeKey  = "@type"
+	valueKey = "value"
+	errorKey = "_error"
+)
+
+// expandValue expands v if it is an object with only "@type" and
+// base64-encoded "value" keys, or expands its elements otherwise. Objects
+// that cannot be decoded are kept as is.
+func expandValue(opts zapproto.DynamicOptions, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		typeURL, ok := v[typeKey].(string)
+		value, hasValue := v[valueKey].(string)
+
+		if !ok || !hasValue || len(v) != 2 {
+			expandFields(opts, v)
+
+			return v
+		}
+
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return v
+		}
+
+		enc := zapcore.NewMapObjectEncoder()
+		opts.Typed = true
+		opts.AnyMessage(valueKey, typeURL, b).AddTo(enc)
+
+		msg, ok := enc.Fields[valueKey].(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		if _, failed := msg[errorKey]; failed {
+			return v
+		}
+
+		return msg
+
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = expandValue(opts, elem)
+		}
+	}
+
+	return v
+}
+
+func expandFields(opts zapproto.DynamicOptions, fields map[string]interface{}) {
+	for k, v := range fields {
+		fields[k] = expandValue(opts, v)
+	}
+}
+
+var traceKeys = []string{"traceId", "trace_id", "trace", "trace.id", "logging.googleapis.com/trace"}
+
+// match reports whether the record passes the level and trace ID filters.
+// Raw lines only pass when no trace ID is given.
+func (cfg config) match(rec *record) bool {
+	if rec.Raw != nil {
+		return cfg.TraceID == ""
+	}
+
+	if !cfg.Level.Enabled(rec.Entry.Level) {
+		return false
+	}
+
+	if cfg.TraceID == "" {
+		return true
+	}
+
+	for _, k := range traceKeys {
+		s, ok := rec.Fields[k].(string)
+		if ok && (s == cfg.TraceID || strings.HasSuffix(s, "/"+cfg.TraceID)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,145 @@
+// Command zapfcat decodes JSON or length-delimited Protobuf log streams and
+// prints them as console output or JSON lines.
+//
+// Usage:
+//
+//	zapfcat [flags] [file ...]
+//
+// The standard input is read when no file is given or the file is "-".
+// Objects with only "@type" and base64-encoded "value" keys, such as
+// unresolved google.protobuf.Any messages, are expanded with the message types
+// of the descriptor set given by -descriptors.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/adzil/zapf/zapproto"
+	"go.uber.org/zap/zapcore"
+)
+
+// config holds the command flags.
+type config struct {
+	Format      string
+	Output      string
+	Color       bool
+	Level       zapcore.Level
+	TraceID     string
+	Descriptors string
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg := config{
+		Level: zapcore.DebugLevel,
+	}
+
+	fs := flag.NewFlagSet("zapfcat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.Format, "format", formatAuto, "input format: auto, json or proto")
+	fs.StringVar(&cfg.Output, "output", outputConsole, "output format: console or json")
+	fs.BoolVar(&cfg.Color, "color", isTerminal(stdout), "colorize console output levels")
+	fs.Var(&cfg.Level, "level", "minimum level of the printed entries")
+	fs.StringVar(&cfg.TraceID, "trace", "", "only print the entries of a trace ID")
+	fs.StringVar(&cfg.Descriptors, "descriptors", "", "binary FileDescriptorSet to expand base64-encoded messages")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := cat(cfg, fs.Args(), stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "zapfcat: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
+
+func cat(cfg config, paths []string, stdin io.Reader, stdout io.Writer) error {
+	w, err := newWriter(cfg)
+	if err != nil {
+		return err
+	}
+
+	var opts zapproto.DynamicOptions
+	if cfg.Descriptors != "" {
+		if opts.Files, err = zapproto.LoadFiles(cfg.Descriptors); err != nil {
+			return err
+		}
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+
+	for _, path := range paths {
+		if err := catFile(cfg, opts, w, path, stdin, out); err != nil {
+			return err
+		}
+	}
+
+	return out.Flush()
+}
+
+func catFile(cfg config, opts zapproto.DynamicOptions, w *writer, path string, stdin io.Reader, out io.Writer) error {
+	in := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	r, err := newReader(cfg.Format, in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if !cfg.match(rec) {
+			continue
+		}
+
+		expandFields(opts, rec.Fields)
+
+		if err := w.Write(out, rec); err != nil {
+			return err
+		}
+	}
+}
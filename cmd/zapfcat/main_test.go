@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/zapecs"
+	"github.com/adzil/zapf/zapproto"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const messageTypeURL = "type.googleapis.com/zapf.marshaler.Message"
+
+func writeDescriptors(t *testing.T) string {
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(anypb.File_google_protobuf_any_proto),
+			protodesc.ToFileDescriptorProto(marshalerpb.File_marshaler_marshaler_proto),
+		},
+	})
+	require.NoError(t, err, "descriptor set should be marshaled")
+
+	path := filepath.Join(t.TempDir(), "descriptors.binpb")
+	require.NoError(t, os.WriteFile(path, b, 0o644), "descriptor set should be written")
+
+	return path
+}
+
+func encodedMessage(t *testing.T) string {
+	b, err := proto.Marshal(&marshalerpb.Message{Text: "hi"})
+	require.NoError(t, err, "message should be marshaled")
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func protoStream(t *testing.T) string {
+	var out bytes.Buffer
+
+	enc := zapproto.NewEncoder()
+	for _, ent := range []zapcore.Entry{
+		{Level: zapcore.DebugLevel, Message: "debug"},
+		{
+			Level:      zapcore.ErrorLevel,
+			Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			LoggerName: "app",
+			Message:    "failed",
+		},
+	} {
+		buf, err := enc.EncodeEntry(ent, []zap.Field{zap.String("traceId", "abc")})
+		require.NoError(t, err, "entry should be encoded")
+
+		out.Write(buf.Bytes())
+		buf.Free()
+	}
+
+	return out.String()
+}
+
+func ecsStream(t *testing.T) string {
+	var out bytes.Buffer
+
+	enc := zapcore.NewJSONEncoder(zapecs.EncoderConfig())
+	core := zapecs.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0xab},
+		SpanID:  trace.SpanID{0xcd},
+	})
+
+	for _, ent := range []zapcore.Entry{
+		{Level: zapcore.DebugLevel, Message: "debug"},
+		{
+			Level:      zapcore.ErrorLevel,
+			Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			LoggerName: "app",
+			Caller:     zapcore.NewEntryCaller(0, "/src/app/main.go", 10, true),
+			Message:    "failed",
+		},
+	} {
+		core.Check(ent, nil).Write(zaptrace.SpanContext(spanCtx), zap.Error(errors.New("boom")))
+	}
+
+	return out.String()
+}
+
+func TestRun(t *testing.T) {
+	type Context struct {
+		Args    []string
+		Input   string
+		Code    int
+		Expects string
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with json input and expanded message": func(t *testing.T, tc *Context) {
+			tc.Args = []string{"-output", "json", "-descriptors", writeDescriptors(t)}
+			tc.Input = `{"level":"info","ts":"2024-01-02T03:04:05Z","logger":"app","caller":"main.go:10","msg":"hello","details":[{"@type":"` + messageTypeURL + `","value":"` + encodedMessage(t) + `"}]}
+not a log entry
+`
+
+			tc.Expects = `{"level":"info","ts":"2024-01-02T03:04:05Z","logger":"app","caller":"main.go:10","msg":"hello","details":[{"@type":"` + messageTypeURL + `","text":"hi"}]}
+not a log entry
+`
+		},
+
+		"with console output": func(t *testing.T, tc *Context) {
+			tc.Args = []string{"-color=false"}
+			tc.Input = `{"level":"warn","msg":"hello","count":1}` + "\n"
+
+			tc.Expects = "WARN\thello\t{\"count\": 1}\n"
+		},
+
+		"with level and trace filters": func(t *testing.T, tc *Context) {
+			tc.Args = []string{"-output", "json", "-level", "info", "-trace", "abc"}
+			tc.Input = `{"level":"debug","msg":"debug","traceId":"abc"}
+{"level":"info","msg":"other","traceId":"def"}
+{"level":"info","msg":"match","trace":"projects/test/traces/abc"}
+not a log entry
+`
+
+			tc.Expects = `{"level":"info","msg":"match","trace":"projects/test/traces/abc"}` + "\n"
+		},
+
+		"with proto input": func(t *testing.T, tc *Context) {
+			tc.Args = []string{"-output", "json", "-level", "info"}
+			tc.Input = protoStream(t)
+
+			tc.Expects = `{"level":"error","ts":"2024-01-02T03:04:05Z","logger":"app","msg":"failed","traceId":"abc"}` + "\n"
+		},
+
+		"with zapecs input": func(t *testing.T, tc *Context) {
+			tc.Args = []string{"-output", "json", "-level", "info", "-trace", trace.TraceID{0xab}.String()}
+			tc.Input = ecsStream(t)
+
+			tc.Expects = `{"level":"error","ts":"2024-01-02T03:04:05Z","logger":"app","caller":"app/main.go:10","msg":"failed",` +
+				`"ecs.version":"8.11.0","error":{"message":"boom","type":"*errors.errorString"},` +
+				`"span.id":"` + trace.SpanID{0xcd}.String() + `","trace.id":"` + trace.TraceID{0xab}.String() + `"}` + "\n"
+		},
+
+		"with truncated proto input": func(t *testing.T, tc *Context) {
+			stream := protoStream(t)
+			tc.Input = stream[:len(stream)-1]
+
+			tc.Code = 1
+		},
+
+		"with unknown output": func(t *testing.T, tc *Context) {
+			tc.Args = []string{"-output", "xml"}
+
+			tc.Code = 1
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			var stdout, stderr bytes.Buffer
+			code := run(tc.Args, strings.NewReader(tc.Input), &stdout, &stderr)
+
+			assert.Equal(t, tc.Code, code, "exit code should match: %s", stderr.String())
+
+			if tc.Code == 0 {
+				assert.Equal(t, tc.Expects, stdout.String(), "output should match")
+			}
+		})
+	}
+}
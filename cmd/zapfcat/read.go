@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adzil/zapf/zapproto"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+const (
+	formatAuto  = "auto"
+	formatJSON  = "json"
+	formatProto = "proto"
+)
+
+// The keys of the entry metadata in JSON lines, in order of precedence. They
+// cover the zap production and development encoder configurations, and the
+// encoder configurations of zapgcp and zapecs.
+var (
+	timeKeys    = []string{"ts", "time", "timestamp", "@timestamp", "T"}
+	levelKeys   = []string{"level", "severity", "log.level", "L"}
+	loggerKeys  = []string{"logger", "log.logger", "N"}
+	callerKeys  = []string{"caller", "C"}
+	messageKeys = []string{"msg", "message", "M"}
+	stackKeys   = []string{"stacktrace", "stack", "error.stack_trace", "S"}
+)
+
+// originKey is the key of the Elastic Common Schema caller object written by
+// zapecs.
+const originKey = "log.origin"
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+}
+
+// record is a decoded log entry. Raw holds the lines of JSON streams that are
+// not log entries, which are printed as is.
+type record struct {
+	Entry  zapcore.Entry
+	Fields map[string]interface{}
+	Raw    []byte
+}
+
+type reader interface {
+	Next() (*record, error)
+}
+
+// newReader constructs a reader of the given format. The auto format detects
+// JSON streams from the leading '{' character followed by a quote, a closing
+// brace or whitespace, which cannot start a length-delimited LogEntry
+// containing a timestamp.
+func newReader(format string, r io.Reader) (reader, error) {
+	br := bufio.NewReader(r)
+
+	if format == formatAuto {
+		format = formatProto
+
+		b, _ := br.Peek(2)
+		if len(b) == 0 || (b[0] == '{' && (len(b) == 1 || bytes.ContainsAny(b[1:], "\"} \t\r\n"))) {
+			format = formatJSON
+		}
+	}
+
+	switch format {
+	case formatJSON:
+		return &jsonReader{r: br}, nil
+
+	case formatProto:
+		return &protoReader{r: br}, nil
+	}
+
+	return nil, fmt.Errorf("unknown input format %q", format)
+}
+
+type jsonReader struct {
+	r *bufio.Reader
+}
+
+func (r *jsonReader) Next() (*record, error) {
+	line, err := r.r.ReadBytes('\n')
+	if len(line) == 0 {
+		return nil, err
+	}
+
+	line = bytes.TrimRight(line, "\r\n")
+
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil || fields == nil {
+		return &record{Raw: line}, nil
+	}
+
+	return jsonRecord(fields), nil
+}
+
+// takeString removes and returns the first string value of keys in fields.
+func takeString(fields map[string]interface{}, keys []string) (string, bool) {
+	for _, k := range keys {
+		if s, ok := fields[k].(string); ok {
+			delete(fields, k)
+
+			return s, true
+		}
+	}
+
+	return "", false
+}
+
+func parseTime(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return parseTime(f)
+
+	case float64:
+		sec, frac := math.Modf(v)
+
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func parseLevel(s string) (zapcore.Level, bool) {
+	switch strings.ToLower(s) {
+	case "default", "notice":
+		return zapcore.InfoLevel, true
+	case "warning":
+		return zapcore.WarnLevel, true
+	case "critical":
+		return zapcore.DPanicLevel, true
+	case "alert":
+		return zapcore.PanicLevel, true
+	case "emergency":
+		return zapcore.FatalLevel, true
+	}
+
+	lvl, err := zapcore.ParseLevel(s)
+
+	return lvl, err == nil
+}
+
+func parseCaller(s string) zapcore.EntryCaller {
+	caller := zapcore.EntryCaller{
+		Defined: true,
+		File:    s,
+	}
+
+	if i := strings.LastIndexByte(s, ':'); i >= 0 {
+		if line, err := strconv.Atoi(s[i+1:]); err == nil {
+			caller.File = s[:i]
+			caller.Line = line
+		}
+	}
+
+	return caller
+}
+
+// parseOrigin parses the log.origin object of the Elastic Common Schema.
+func parseOrigin(v interface{}) (zapcore.EntryCaller, bool) {
+	origin, ok := v.(map[string]interface{})
+	if !ok {
+		return zapcore.EntryCaller{}, false
+	}
+
+	file, ok := origin["file"].(map[string]interface{})
+	if !ok {
+		return zapcore.EntryCaller{}, false
+	}
+
+	name, ok := file["name"].(string)
+	if !ok {
+		return zapcore.EntryCaller{}, false
+	}
+
+	caller := zapcore.EntryCaller{
+		Defined: true,
+		File:    name,
+	}
+
+	switch line := file["line"].(type) {
+	case json.Number:
+		n, _ := line.Int64()
+		caller.Line = int(n)
+	case float64:
+		caller.Line = int(line)
+	}
+
+	caller.Function, _ = origin["function"].(string)
+
+	return caller, true
+}
+
+func jsonRecord(fields map[string]interface{}) *record {
+	rec := &record{
+		Entry: zapcore.Entry{
+			Level: zapcore.InfoLevel,
+		},
+		Fields: fields,
+	}
+
+	for _, k := range timeKeys {
+		if t, ok := parseTime(fields[k]); ok {
+			rec.Entry.Time = t
+			delete(fields, k)
+
+			break
+		}
+	}
+
+	for _, k := range levelKeys {
+		s, ok := fields[k].(string)
+		if !ok {
+			continue
+		}
+
+		if lvl, ok := parseLevel(s); ok {
+			rec.Entry.Level = lvl
+			delete(fields, k)
+
+			break
+		}
+	}
+
+	rec.Entry.LoggerName, _ = takeString(fields, loggerKeys)
+	rec.Entry.Message, _ = takeString(fields, messageKeys)
+	rec.Entry.Stack, _ = takeString(fields, stackKeys)
+
+	if s, ok := takeString(fields, callerKeys); ok {
+		rec.Entry.Caller = parseCaller(s)
+	} else if caller, ok := parseOrigin(fields[originKey]); ok {
+		rec.Entry.Caller = caller
+		delete(fields, originKey)
+	}
+
+	return rec
+}
+
+type protoReader struct {
+	r *bufio.Reader
+}
+
+func protoLevel(lvl int32) zapcore.Level {
+	if lvl == 0 {
+		return zapcore.InfoLevel
+	}
+
+	// The LogEntry levels start from debug at 1.
+	return zapcore.Level(lvl) + zapcore.DebugLevel - 1
+}
+
+func (r *protoReader) Next() (*record, error) {
+	entry := &zapproto.LogEntry{}
+	if err := protodelim.UnmarshalFrom(r.r, entry); err != nil {
+		return nil, err
+	}
+
+	rec := &record{
+		Entry: zapcore.Entry{
+			Level:      protoLevel(int32(entry.GetLevel())),
+			LoggerName: entry.GetLogger(),
+			Message:    entry.GetMessage(),
+			Stack:      entry.GetStack(),
+		},
+		Fields: entry.GetFields().AsMap(),
+	}
+
+	if entry.Timestamp != nil {
+		rec.Entry.Time = entry.GetTimestamp().AsTime()
+	}
+
+	if caller := entry.GetCaller(); caller != nil {
+		rec.Entry.Caller = zapcore.EntryCaller{
+			Defined:  true,
+			File:     caller.GetFile(),
+			Line:     int(caller.GetLine()),
+			Function: caller.GetFunction(),
+		}
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestJSONRecord(t *testing.T) {
+	type Context struct {
+		Input   string
+		Expects record
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with production keys": func(t *testing.T, tc *Context) {
+			tc.Input = `{"level":"error","ts":1704164645.5,"logger":"app","caller":"pkg/main.go:42","msg":"failed","stacktrace":"stack","key":"value"}`
+
+			tc.Expects = record{
+				Entry: zapcore.Entry{
+					Level:      zapcore.ErrorLevel,
+					Time:       time.Unix(1704164645, 5e8),
+					LoggerName: "app",
+					Caller:     zapcore.EntryCaller{Defined: true, File: "pkg/main.go", Line: 42},
+					Message:    "failed",
+					Stack:      "stack",
+				},
+				Fields: map[string]interface{}{"key": "value"},
+			}
+		},
+
+		"with severity and message object": func(t *testing.T, tc *Context) {
+			tc.Input = `{"severity":"WARNING","time":"2024-01-02T03:04:05.000Z","message":{"text":"hello"}}`
+
+			tc.Expects = record{
+				Entry: zapcore.Entry{
+					Level: zapcore.WarnLevel,
+					Time:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+				Fields: map[string]interface{}{
+					"message": map[string]interface{}{"text": "hello"},
+				},
+			}
+		},
+
+		"with ecs keys": func(t *testing.T, tc *Context) {
+			tc.Input = `{"@timestamp":"2024-01-02T03:04:05.000Z","log.level":"error","log.logger":"app","log.origin":{"file":{"name":"pkg/main.go","line":42},"function":"main.main"},"message":"failed","error.stack_trace":"stack"}`
+
+			tc.Expects = record{
+				Entry: zapcore.Entry{
+					Level:      zapcore.ErrorLevel,
+					Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+					LoggerName: "app",
+					Caller:     zapcore.EntryCaller{Defined: true, File: "pkg/main.go", Line: 42, Function: "main.main"},
+					Message:    "failed",
+					Stack:      "stack",
+				},
+				Fields: map[string]interface{}{},
+			}
+		},
+
+		"with unknown level": func(t *testing.T, tc *Context) {
+			tc.Input = `{"level":"verbose"}`
+
+			tc.Expects = record{
+				Entry: zapcore.Entry{
+					Level: zapcore.InfoLevel,
+				},
+				Fields: map[string]interface{}{"level": "verbose"},
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			var fields map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tc.Input), &fields), "input should be valid json")
+
+			rec := jsonRecord(fields)
+
+			assert.True(t, tc.Expects.Entry.Time.Equal(rec.Entry.Time), "time should match: %s", rec.Entry.Time)
+			rec.Entry.Time = tc.Expects.Entry.Time
+
+			assert.Equal(t, tc.Expects, *rec, "record should match")
+		})
+	}
+}
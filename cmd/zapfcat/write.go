@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	outputConsole = "console"
+	outputJSON    = "json"
+)
+
+// writer encodes records with zap encoders. Entries without time are encoded
+// without the time key.
+type writer struct {
+	enc       zapcore.Encoder
+	encNoTime zapcore.Encoder
+}
+
+func newWriter(cfg config) (*writer, error) {
+	var encCfg zapcore.EncoderConfig
+
+	newEncoder := zapcore.NewJSONEncoder
+
+	switch cfg.Output {
+	case outputConsole:
+		encCfg = zap.NewDevelopmentEncoderConfig()
+		encCfg.EncodeCaller = zapcore.FullCallerEncoder
+
+		if cfg.Color {
+			encCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+
+		newEncoder = zapcore.NewConsoleEncoder
+
+	case outputJSON:
+		encCfg = zap.NewProductionEncoderConfig()
+		encCfg.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+		encCfg.EncodeCaller = zapcore.FullCallerEncoder
+
+	default:
+		return nil, fmt.Errorf("unknown output format %q", cfg.Output)
+	}
+
+	w := &writer{
+		enc: newEncoder(encCfg),
+	}
+
+	encCfg.TimeKey = ""
+	w.encNoTime = newEncoder(encCfg)
+
+	return w, nil
+}
+
+func (w *writer) Write(out io.Writer, rec *record) error {
+	if rec.Raw != nil {
+		_, err := fmt.Fprintf(out, "%s\n", rec.Raw)
+
+		return err
+	}
+
+	keys := make([]string, 0, len(rec.Fields))
+	for k := range rec.Fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, len(keys))
+	for i, k := range keys {
+		// json.Number implements fmt.Stringer, which zap.Any encodes as a
+		// string.
+		if n, ok := rec.Fields[k].(json.Number); ok {
+			fields[i] = zap.Reflect(k, n)
+
+			continue
+		}
+
+		fields[i] = zap.Any(k, rec.Fields[k])
+	}
+
+	enc := w.enc
+	if rec.Entry.Time.IsZero() {
+		enc = w.encNoTime
+	}
+
+	buf, err := enc.EncodeEntry(rec.Entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	_, err = out.Write(buf.Bytes())
+
+	return err
+}
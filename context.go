@@ -0,0 +1,212 @@
+// Package zapf attaches zap fields to context.Context and retrieves loggers
+// that include them.
+package zapf
+
+import (
+	"context"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type fieldsKey struct{}
+
+// fieldsNode is an immutable list of the fields attached to a
+// context.Context. Deriving a context only allocates a node pointing to the
+// fields of its parent, so the fields are safe to share across goroutines.
+type fieldsNode struct {
+	Parent *fieldsNode
+	Fields []zap.Field
+	Len    int
+}
+
+// WithFields returns a copy of the parent context with the fields attached.
+// The fields are appended after the fields already attached to the parent.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	parent, _ := ctx.Value(fieldsKey{}).(*fieldsNode)
+
+	node := &fieldsNode{
+		Parent: parent,
+		Fields: append([]zap.Field(nil), fields...),
+		Len:    len(fields),
+	}
+	if parent != nil {
+		node.Len += parent.Len
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, node)
+}
+
+// Fields returns the fields attached to the context in the order they are
+// attached. It returns nil if no field is attached.
+func Fields(ctx context.Context) []zap.Field {
+	node, _ := ctx.Value(fieldsKey{}).(*fieldsNode)
+	if node == nil {
+		return nil
+	}
+
+	fields := make([]zap.Field, node.Len)
+
+	for i := node.Len; node != nil; node = node.Parent {
+		i -= len(node.Fields)
+		copy(fields[i:], node.Fields)
+	}
+
+	return fields
+}
+
+// Options configures the loggers constructed by Options.Logger.
+type Options struct {
+	// Context constructs fields from the context that are added after the
+	// attached fields, such as zaptrace.Context. No fields are constructed
+	// from the context when it is empty.
+	Context []func(ctx context.Context) zap.Field
+}
+
+// dedupField holds a field with the keys it encodes.
+type dedupField struct {
+	Field zap.Field
+	// Keys are the encoded keys prefixed by the namespaces opened before the
+	// field.
+	Keys []string
+	// Expanded are the keyed fields encoded by an inline field, in the same
+	// order as Keys.
+	Expanded []zap.Field
+	// Nested reports whether the field opens a namespace.
+	Nested bool
+}
+
+// dedupFieldsOf returns the keys encoded by the fields. Inline fields have no
+// key and are encoded to find the keys they add instead.
+func dedupFieldsOf(fields []zap.Field) []dedupField {
+	dfs := make([]dedupField, len(fields))
+
+	prefix := ""
+
+	for i, f := range fields {
+		df := dedupField{
+			Field: f,
+		}
+
+		switch f.Type {
+		case zapcore.NamespaceType:
+			df.Nested = true
+			prefix += f.Key + "."
+
+		case zapcore.InlineMarshalerType:
+			for _, ef := range fieldenc.Expand([]zap.Field{f}) {
+				if ef.Type == zapcore.NamespaceType {
+					df.Nested = true
+					prefix += ef.Key + "."
+
+					continue
+				}
+
+				df.Keys = append(df.Keys, prefix+ef.Key)
+				df.Expanded = append(df.Expanded, ef)
+			}
+
+		default:
+			df.Keys = []string{prefix + f.Key}
+		}
+
+		dfs[i] = df
+	}
+
+	return dfs
+}
+
+// dedup removes the fields whose keys are replaced by later fields within the
+// same namespace. The last field of each key is kept in place. Inline fields
+// whose keys are only partly replaced are expanded into their remaining keyed
+// fields.
+func dedup(fields []zap.Field) []zap.Field {
+	dfs := dedupFieldsOf(fields)
+
+	seen := make(map[string]struct{}, len(fields))
+	deduped := make([]zap.Field, 0, len(fields))
+
+	for i := len(dfs) - 1; i >= 0; i-- {
+		df := dfs[i]
+
+		if df.Field.Type == zapcore.SkipType {
+			continue
+		}
+
+		replaced := 0
+
+		for _, key := range df.Keys {
+			if _, ok := seen[key]; ok {
+				replaced++
+			}
+		}
+
+		switch {
+		// Fields opening namespaces are never deduplicated as they nest the
+		// fields after them.
+		case df.Nested || replaced == 0:
+			deduped = append(deduped, df.Field)
+
+		case replaced < len(df.Keys):
+			for j := len(df.Expanded) - 1; j >= 0; j-- {
+				if _, ok := seen[df.Keys[j]]; !ok {
+					deduped = append(deduped, df.Expanded[j])
+				}
+			}
+		}
+
+		for _, key := range df.Keys {
+			seen[key] = struct{}{}
+		}
+	}
+
+	for i, j := 0, len(deduped)-1; i < j; i, j = i+1, j-1 {
+		deduped[i], deduped[j] = deduped[j], deduped[i]
+	}
+
+	return deduped
+}
+
+// Fields returns the fields attached to the context followed by the fields
+// constructed by the context functions. Fields with the same key are
+// deduplicated with the later field replacing the earlier one. Inline fields,
+// such as zaptrace.Context, are deduplicated by the keys they encode.
+func (opts Options) Fields(ctx context.Context) []zap.Field {
+	fields := Fields(ctx)
+
+	for _, fn := range opts.Context {
+		fields = append(fields, fn(ctx))
+	}
+
+	return dedup(fields)
+}
+
+// Logger returns a child of the logger that includes the fields of the
+// context. See Options.Fields for details. zap.L is used when the logger is
+// nil.
+func (opts Options) Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if logger == nil {
+		logger = zap.L()
+	}
+
+	fields := opts.Fields(ctx)
+	if len(fields) == 0 {
+		return logger
+	}
+
+	return logger.With(fields...)
+}
+
+// Logger returns a child of the logger that includes the fields attached to
+// the context with the default options. See Options.Logger for details. The
+// default options construct no fields from the context, so the trace context
+// is only included when it is attached with WithFields. Use Options.Context
+// with zaptrace.Context to include the span context of each context instead.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	return Options{}.Logger(ctx, logger)
+}
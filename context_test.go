@@ -0,0 +1,229 @@
+package zapf_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/adzil/zapf"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type traceKey struct{}
+
+// traceMarshaler mimics the inline trace context fields of zaptrace.
+type traceMarshaler string
+
+func (m traceMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if m != "" {
+		enc.AddString("traceId", string(m))
+	}
+
+	return nil
+}
+
+func traceContext(ctx context.Context) zap.Field {
+	id, _ := ctx.Value(traceKey{}).(string)
+
+	return zap.Inline(traceMarshaler(id))
+}
+
+// spanMarshaler mimics the inline span fields of zaptrace.
+type spanMarshaler struct {
+	TraceID string
+	SpanID  string
+	Name    string
+}
+
+func (m spanMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("traceId", m.TraceID)
+	enc.AddString("spanId", m.SpanID)
+
+	return enc.AddObject("span", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("name", m.Name)
+
+		return nil
+	}))
+}
+
+type labelMarshaler struct {
+	Key   string
+	Value string
+}
+
+func (m labelMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString(m.Key, m.Value)
+
+	return nil
+}
+
+func TestOptions_Logger(t *testing.T) {
+	type Context struct {
+		Options zapf.Options
+		Context context.Context
+		Expects map[string]interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"without fields": func(t *testing.T, tc *Context) {
+			tc.Expects = map[string]interface{}{}
+		},
+
+		"with derived fields": func(t *testing.T, tc *Context) {
+			ctx := zapf.WithFields(tc.Context, zap.String("tenant", "a"), zap.Int("attempt", 1))
+			ctx = zapf.WithFields(ctx)
+			tc.Context = zapf.WithFields(ctx, zap.String("requestId", "b"), zap.Int("attempt", 2))
+
+			tc.Expects = map[string]interface{}{
+				"tenant":    "a",
+				"requestId": "b",
+				"attempt":   int64(2),
+			}
+		},
+
+		"with namespace": func(t *testing.T, tc *Context) {
+			tc.Context = zapf.WithFields(tc.Context,
+				zap.String("key", "a"),
+				zap.Namespace("ns"),
+				zap.String("key", "b"),
+				zap.Skip(),
+			)
+
+			tc.Expects = map[string]interface{}{
+				"key": "a",
+				"ns":  map[string]interface{}{"key": "b"},
+			}
+		},
+
+		"with trace last": func(t *testing.T, tc *Context) {
+			tc.Options.Context = append(tc.Options.Context, traceContext)
+			ctx := context.WithValue(tc.Context, traceKey{}, "abc")
+			tc.Context = zapf.WithFields(ctx, zap.Inline(traceMarshaler("def")), zap.String("tenant", "a"))
+
+			tc.Expects = map[string]interface{}{
+				"tenant":  "a",
+				"traceId": "abc",
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{
+				Context: context.Background(),
+			}
+			v(t, tc)
+
+			core, logs := observer.New(zapcore.DebugLevel)
+			tc.Options.Logger(tc.Context, zap.New(core)).Info("test")
+
+			entries := logs.AllUntimed()
+			if assert.Len(t, entries, 1, "entries should be logged") {
+				assert.Equal(t, tc.Expects, entries[0].ContextMap(), "fields should match")
+			}
+		})
+	}
+}
+
+func TestOptions_Fields(t *testing.T) {
+	opts := zapf.Options{
+		Context: []func(ctx context.Context) zap.Field{traceContext},
+	}
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "abc")
+	ctx = zapf.WithFields(ctx, zap.String("tenant", "a"), zap.Inline(traceMarshaler("def")))
+
+	assert.Equal(t, []zap.Field{
+		zap.String("tenant", "a"),
+		zap.Inline(traceMarshaler("abc")),
+	}, opts.Fields(ctx), "trace field should be added last")
+}
+
+func TestOptions_Fields_Inline(t *testing.T) {
+	type Context struct {
+		Input   []zap.Field
+		Expects string
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with inline fields of the same type": func(t *testing.T, tc *Context) {
+			tc.Input = []zap.Field{
+				zap.Inline(labelMarshaler{Key: "a", Value: "1"}),
+				zap.Inline(labelMarshaler{Key: "b", Value: "2"}),
+				zap.Inline(labelMarshaler{Key: "a", Value: "3"}),
+			}
+
+			tc.Expects = `{"b":"2","a":"3"}`
+		},
+
+		"with partly replaced inline field": func(t *testing.T, tc *Context) {
+			tc.Input = []zap.Field{
+				zap.Inline(spanMarshaler{TraceID: "abc", SpanID: "def", Name: "test"}),
+				zap.Inline(traceMarshaler("ghi")),
+			}
+
+			tc.Expects = `{"spanId":"def","span":{"name":"test"},"traceId":"ghi"}`
+		},
+
+		"with fully replaced inline field": func(t *testing.T, tc *Context) {
+			tc.Input = []zap.Field{
+				zap.Inline(traceMarshaler("abc")),
+				zap.Inline(spanMarshaler{TraceID: "def", SpanID: "ghi", Name: "test"}),
+			}
+
+			tc.Expects = `{"traceId":"def","spanId":"ghi","span":{"name":"test"}}`
+		},
+
+		"with inline field in namespace": func(t *testing.T, tc *Context) {
+			tc.Input = []zap.Field{
+				zap.Inline(traceMarshaler("abc")),
+				zap.Namespace("ns"),
+				zap.Inline(traceMarshaler("def")),
+			}
+
+			tc.Expects = `{"traceId":"abc","ns":{"traceId":"def"}}`
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			fields := zapf.Options{}.Fields(zapf.WithFields(context.Background(), tc.Input...))
+
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+			if assert.NoError(t, err, "fields should be encoded") {
+				assert.Equal(t, tc.Expects+"\n", buf.String(), "encoded fields should match")
+			}
+		})
+	}
+}
+
+func TestWithFields_Concurrent(t *testing.T) {
+	fields := []zap.Field{zap.String("tenant", "a")}
+	parent := zapf.WithFields(context.Background(), fields...)
+	fields[0] = zap.String("tenant", "b")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			ctx := zapf.WithFields(parent, zap.Int("worker", i))
+
+			assert.Equal(t, []zap.Field{
+				zap.String("tenant", "a"),
+				zap.Int("worker", i),
+			}, zapf.Fields(ctx), "fields should not be shared across derived contexts")
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, []zap.Field{zap.String("tenant", "a")}, zapf.Fields(parent), "parent fields should be immutable")
+	assert.Nil(t, zapf.Fields(context.Background()), "fields should be nil without attached fields")
+}
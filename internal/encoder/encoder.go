@@ -0,0 +1,99 @@
+package encoder
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Encoder appends values into either a zapcore.ArrayEncoder or a key of a
+// zapcore.ObjectEncoder through FieldEncoder, so the same walk can serialize
+// both array elements and object fields.
+type Encoder interface {
+	AppendObject(zapcore.ObjectMarshaler) error
+	AppendArray(zapcore.ArrayMarshaler) error
+	AppendReflected(interface{}) error
+	AppendBool(bool)
+	AppendString(string)
+	AppendComplex128(complex128)
+	AppendFloat32(float32)
+	AppendFloat64(float64)
+	AppendInt32(int32)
+	AppendUint32(uint32)
+	AppendInt64(int64)
+	AppendUint64(uint64)
+	AppendDuration(time.Duration)
+	AppendTime(time.Time)
+}
+
+var _ Encoder = zapcore.ArrayEncoder(nil)
+
+// FieldEncoder appends each value into the key of an object encoder.
+type FieldEncoder struct {
+	enc zapcore.ObjectEncoder
+	key string
+}
+
+// Field constructs a FieldEncoder that appends values into the key of enc.
+func Field(enc zapcore.ObjectEncoder, key string) FieldEncoder {
+	return FieldEncoder{
+		enc: enc,
+		key: key,
+	}
+}
+
+func (e FieldEncoder) AppendObject(ms zapcore.ObjectMarshaler) error {
+	return e.enc.AddObject(e.key, ms)
+}
+
+func (e FieldEncoder) AppendArray(ms zapcore.ArrayMarshaler) error {
+	return e.enc.AddArray(e.key, ms)
+}
+
+func (e FieldEncoder) AppendReflected(v interface{}) error {
+	return e.enc.AddReflected(e.key, v)
+}
+
+func (e FieldEncoder) AppendBool(b bool) {
+	e.enc.AddBool(e.key, b)
+}
+
+func (e FieldEncoder) AppendString(s string) {
+	e.enc.AddString(e.key, s)
+}
+
+func (e FieldEncoder) AppendComplex128(c complex128) {
+	e.enc.AddComplex128(e.key, c)
+}
+
+func (e FieldEncoder) AppendFloat32(f float32) {
+	e.enc.AddFloat32(e.key, f)
+}
+
+func (e FieldEncoder) AppendFloat64(f float64) {
+	e.enc.AddFloat64(e.key, f)
+}
+
+func (e FieldEncoder) AppendInt32(i int32) {
+	e.enc.AddInt32(e.key, i)
+}
+
+func (e FieldEncoder) AppendUint32(u uint32) {
+	e.enc.AddUint32(e.key, u)
+}
+
+func (e FieldEncoder) AppendInt64(i int64) {
+	e.enc.AddInt64(e.key, i)
+}
+
+func (e FieldEncoder) AppendUint64(u uint64) {
+	e.enc.AddUint64(e.key, u)
+}
+
+func (e FieldEncoder) AppendDuration(d time.Duration) {
+	e.enc.AddDuration(e.key, d)
+}
+
+func (e FieldEncoder) AppendTime(t time.Time) {
+	e.enc.AddTime(e.key, t)
+}
@@ -0,0 +1,315 @@
+package fieldenc
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Converter converts the values added to ObjectEncoder and ArrayEncoder into
+// values of V. Integers are widened into Int64 and Uint64, floats into
+// Float64, and byte strings and complex numbers are converted into strings.
+type Converter[V any] interface {
+	Object(kvs []KeyValue[V]) V
+	Array(vs []V) V
+	Binary(b []byte) V
+	Bool(b bool) V
+	Duration(d time.Duration) V
+	Float64(f float64) V
+	Int64(i int64) V
+	String(s string) V
+	Time(t time.Time) V
+	Uint64(u uint64) V
+	Reflected(v interface{}) (V, error)
+}
+
+// ObjectEncoder is a zapcore.ObjectEncoder that converts the encoded fields
+// with a Converter. Nested objects and namespaces are converted with
+// Converter.Object.
+type ObjectEncoder[V any] struct {
+	conv Converter[V]
+	ns   *Namespace[V]
+}
+
+var _ zapcore.ObjectEncoder = (*ObjectEncoder[any])(nil)
+
+// NewObjectEncoder constructs an empty ObjectEncoder.
+func NewObjectEncoder[V any](conv Converter[V]) *ObjectEncoder[V] {
+	return &ObjectEncoder[V]{
+		conv: conv,
+		ns:   &Namespace[V]{},
+	}
+}
+
+// KeyValues returns the encoded fields.
+func (enc *ObjectEncoder[V]) KeyValues() []KeyValue[V] {
+	return enc.ns.KeyValues(enc.conv.Object)
+}
+
+// Clone returns a copy of the encoder that does not share its fields.
+func (enc *ObjectEncoder[V]) Clone() *ObjectEncoder[V] {
+	return &ObjectEncoder[V]{
+		conv: enc.conv,
+		ns:   enc.ns.Clone(),
+	}
+}
+
+func (enc *ObjectEncoder[V]) AddArray(key string, ms zapcore.ArrayMarshaler) error {
+	aenc := NewArrayEncoder(enc.conv)
+	if err := ms.MarshalLogArray(aenc); err != nil {
+		return err
+	}
+
+	enc.ns.Add(key, enc.conv.Array(aenc.Values()))
+
+	return nil
+}
+
+func (enc *ObjectEncoder[V]) AddObject(key string, ms zapcore.ObjectMarshaler) error {
+	oenc := NewObjectEncoder(enc.conv)
+	if err := ms.MarshalLogObject(oenc); err != nil {
+		return err
+	}
+
+	enc.ns.Add(key, enc.conv.Object(oenc.KeyValues()))
+
+	return nil
+}
+
+func (enc *ObjectEncoder[V]) AddBinary(key string, b []byte) {
+	enc.ns.Add(key, enc.conv.Binary(b))
+}
+
+func (enc *ObjectEncoder[V]) AddByteString(key string, b []byte) {
+	enc.ns.Add(key, enc.conv.String(string(b)))
+}
+
+func (enc *ObjectEncoder[V]) AddBool(key string, b bool) {
+	enc.ns.Add(key, enc.conv.Bool(b))
+}
+
+func (enc *ObjectEncoder[V]) AddComplex128(key string, c complex128) {
+	enc.ns.Add(key, enc.conv.String(strconv.FormatComplex(c, 'g', -1, 128)))
+}
+
+func (enc *ObjectEncoder[V]) AddComplex64(key string, c complex64) {
+	enc.ns.Add(key, enc.conv.String(strconv.FormatComplex(complex128(c), 'g', -1, 64)))
+}
+
+func (enc *ObjectEncoder[V]) AddDuration(key string, d time.Duration) {
+	enc.ns.Add(key, enc.conv.Duration(d))
+}
+
+func (enc *ObjectEncoder[V]) AddFloat64(key string, f float64) {
+	enc.ns.Add(key, enc.conv.Float64(f))
+}
+
+func (enc *ObjectEncoder[V]) AddFloat32(key string, f float32) {
+	enc.ns.Add(key, enc.conv.Float64(float64(f)))
+}
+
+func (enc *ObjectEncoder[V]) AddInt(key string, i int) {
+	enc.ns.Add(key, enc.conv.Int64(int64(i)))
+}
+
+func (enc *ObjectEncoder[V]) AddInt64(key string, i int64) {
+	enc.ns.Add(key, enc.conv.Int64(i))
+}
+
+func (enc *ObjectEncoder[V]) AddInt32(key string, i int32) {
+	enc.ns.Add(key, enc.conv.Int64(int64(i)))
+}
+
+func (enc *ObjectEncoder[V]) AddInt16(key string, i int16) {
+	enc.ns.Add(key, enc.conv.Int64(int64(i)))
+}
+
+func (enc *ObjectEncoder[V]) AddInt8(key string, i int8) {
+	enc.ns.Add(key, enc.conv.Int64(int64(i)))
+}
+
+func (enc *ObjectEncoder[V]) AddString(key, value string) {
+	enc.ns.Add(key, enc.conv.String(value))
+}
+
+func (enc *ObjectEncoder[V]) AddTime(key string, t time.Time) {
+	enc.ns.Add(key, enc.conv.Time(t))
+}
+
+func (enc *ObjectEncoder[V]) AddUint(key string, u uint) {
+	enc.ns.Add(key, enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ObjectEncoder[V]) AddUint64(key string, u uint64) {
+	enc.ns.Add(key, enc.conv.Uint64(u))
+}
+
+func (enc *ObjectEncoder[V]) AddUint32(key string, u uint32) {
+	enc.ns.Add(key, enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ObjectEncoder[V]) AddUint16(key string, u uint16) {
+	enc.ns.Add(key, enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ObjectEncoder[V]) AddUint8(key string, u uint8) {
+	enc.ns.Add(key, enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ObjectEncoder[V]) AddUintptr(key string, u uintptr) {
+	enc.ns.Add(key, enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ObjectEncoder[V]) AddReflected(key string, v interface{}) error {
+	cv, err := enc.conv.Reflected(v)
+	if err != nil {
+		return err
+	}
+
+	enc.ns.Add(key, cv)
+
+	return nil
+}
+
+func (enc *ObjectEncoder[V]) OpenNamespace(key string) {
+	enc.ns.Open(key)
+}
+
+// ArrayEncoder is a zapcore.ArrayEncoder that converts the encoded elements
+// with a Converter.
+type ArrayEncoder[V any] struct {
+	conv   Converter[V]
+	values []V
+}
+
+var _ zapcore.ArrayEncoder = (*ArrayEncoder[any])(nil)
+
+// NewArrayEncoder constructs an empty ArrayEncoder.
+func NewArrayEncoder[V any](conv Converter[V]) *ArrayEncoder[V] {
+	return &ArrayEncoder[V]{
+		conv: conv,
+	}
+}
+
+// Values returns the encoded elements.
+func (enc *ArrayEncoder[V]) Values() []V {
+	return enc.values
+}
+
+func (enc *ArrayEncoder[V]) appendValue(v V) {
+	enc.values = append(enc.values, v)
+}
+
+func (enc *ArrayEncoder[V]) AppendArray(ms zapcore.ArrayMarshaler) error {
+	aenc := NewArrayEncoder(enc.conv)
+	if err := ms.MarshalLogArray(aenc); err != nil {
+		return err
+	}
+
+	enc.appendValue(enc.conv.Array(aenc.Values()))
+
+	return nil
+}
+
+func (enc *ArrayEncoder[V]) AppendObject(ms zapcore.ObjectMarshaler) error {
+	oenc := NewObjectEncoder(enc.conv)
+	if err := ms.MarshalLogObject(oenc); err != nil {
+		return err
+	}
+
+	enc.appendValue(enc.conv.Object(oenc.KeyValues()))
+
+	return nil
+}
+
+func (enc *ArrayEncoder[V]) AppendReflected(v interface{}) error {
+	cv, err := enc.conv.Reflected(v)
+	if err != nil {
+		return err
+	}
+
+	enc.appendValue(cv)
+
+	return nil
+}
+
+func (enc *ArrayEncoder[V]) AppendBool(b bool) {
+	enc.appendValue(enc.conv.Bool(b))
+}
+
+func (enc *ArrayEncoder[V]) AppendByteString(b []byte) {
+	enc.appendValue(enc.conv.String(string(b)))
+}
+
+func (enc *ArrayEncoder[V]) AppendComplex128(c complex128) {
+	enc.appendValue(enc.conv.String(strconv.FormatComplex(c, 'g', -1, 128)))
+}
+
+func (enc *ArrayEncoder[V]) AppendComplex64(c complex64) {
+	enc.appendValue(enc.conv.String(strconv.FormatComplex(complex128(c), 'g', -1, 64)))
+}
+
+func (enc *ArrayEncoder[V]) AppendDuration(d time.Duration) {
+	enc.appendValue(enc.conv.Duration(d))
+}
+
+func (enc *ArrayEncoder[V]) AppendFloat64(f float64) {
+	enc.appendValue(enc.conv.Float64(f))
+}
+
+func (enc *ArrayEncoder[V]) AppendFloat32(f float32) {
+	enc.appendValue(enc.conv.Float64(float64(f)))
+}
+
+func (enc *ArrayEncoder[V]) AppendInt(i int) {
+	enc.appendValue(enc.conv.Int64(int64(i)))
+}
+
+func (enc *ArrayEncoder[V]) AppendInt64(i int64) {
+	enc.appendValue(enc.conv.Int64(i))
+}
+
+func (enc *ArrayEncoder[V]) AppendInt32(i int32) {
+	enc.appendValue(enc.conv.Int64(int64(i)))
+}
+
+func (enc *ArrayEncoder[V]) AppendInt16(i int16) {
+	enc.appendValue(enc.conv.Int64(int64(i)))
+}
+
+func (enc *ArrayEncoder[V]) AppendInt8(i int8) {
+	enc.appendValue(enc.conv.Int64(int64(i)))
+}
+
+func (enc *ArrayEncoder[V]) AppendString(s string) {
+	enc.appendValue(enc.conv.String(s))
+}
+
+func (enc *ArrayEncoder[V]) AppendTime(t time.Time) {
+	enc.appendValue(enc.conv.Time(t))
+}
+
+func (enc *ArrayEncoder[V]) AppendUint(u uint) {
+	enc.appendValue(enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ArrayEncoder[V]) AppendUint64(u uint64) {
+	enc.appendValue(enc.conv.Uint64(u))
+}
+
+func (enc *ArrayEncoder[V]) AppendUint32(u uint32) {
+	enc.appendValue(enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ArrayEncoder[V]) AppendUint16(u uint16) {
+	enc.appendValue(enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ArrayEncoder[V]) AppendUint8(u uint8) {
+	enc.appendValue(enc.conv.Uint64(uint64(u)))
+}
+
+func (enc *ArrayEncoder[V]) AppendUintptr(u uintptr) {
+	enc.appendValue(enc.conv.Uint64(uint64(u)))
+}
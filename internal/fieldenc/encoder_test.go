@@ -0,0 +1,139 @@
+package fieldenc_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// testConverter converts the values into Go values, with objects converted
+// into key-values to assert their order.
+type testConverter struct{}
+
+func (testConverter) Object(kvs []fieldenc.KeyValue[interface{}]) interface{} {
+	return kvs
+}
+
+func (testConverter) Array(vs []interface{}) interface{} {
+	return vs
+}
+
+func (testConverter) Binary(b []byte) interface{} {
+	return b
+}
+
+func (testConverter) Bool(b bool) interface{} {
+	return b
+}
+
+func (testConverter) Duration(d time.Duration) interface{} {
+	return d
+}
+
+func (testConverter) Float64(f float64) interface{} {
+	return f
+}
+
+func (testConverter) Int64(i int64) interface{} {
+	return i
+}
+
+func (testConverter) String(s string) interface{} {
+	return s
+}
+
+func (testConverter) Time(t time.Time) interface{} {
+	return t
+}
+
+func (testConverter) Uint64(u uint64) interface{} {
+	return u
+}
+
+func (testConverter) Reflected(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, errors.New("nil reflected value")
+	}
+
+	return v, nil
+}
+
+type kvs = []fieldenc.KeyValue[interface{}]
+
+func TestObjectEncoder(t *testing.T) {
+	enc := fieldenc.NewObjectEncoder[interface{}](testConverter{})
+
+	for _, f := range []zap.Field{
+		zap.Int8("int", 1),
+		zap.Uint32("uint", 2),
+		zap.Float32("float", 0.5),
+		zap.ByteString("bytes", []byte("a")),
+		zap.Complex64("complex", 1+2i),
+		zap.Reflect("reflected", nil),
+		zap.Namespace("ns"),
+		zap.Ints("ints", []int{1, 2}),
+		zap.Namespace("inner"),
+		zap.Object("obj", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddBool("bool", true)
+
+			return nil
+		})),
+	} {
+		f.AddTo(enc)
+	}
+
+	clone := enc.Clone()
+	clone.AddString("cloned", "value")
+
+	expects := kvs{
+		{Key: "int", Value: int64(1)},
+		{Key: "uint", Value: uint64(2)},
+		{Key: "float", Value: float64(0.5)},
+		{Key: "bytes", Value: "a"},
+		{Key: "complex", Value: "(1+2i)"},
+		{Key: "reflectedError", Value: "nil reflected value"},
+		{Key: "ns", Value: kvs{
+			{Key: "ints", Value: []interface{}{int64(1), int64(2)}},
+			{Key: "inner", Value: kvs{
+				{Key: "obj", Value: kvs{{Key: "bool", Value: true}}},
+			}},
+		}},
+	}
+
+	assert.Equal(t, expects, enc.KeyValues(), "fields should match")
+	assert.Equal(t, "cloned", clone.KeyValues()[6].Value.(kvs)[1].Value.(kvs)[1].Key,
+		"cloned fields should be added to the innermost namespace")
+	assert.Equal(t, expects, enc.KeyValues(), "fields should not be changed by the clone")
+}
+
+type traceMarshaler struct{}
+
+func (traceMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("traceId", "abc")
+	enc.AddString("spanId", "def")
+
+	return nil
+}
+
+func TestExpand(t *testing.T) {
+	obj := traceMarshaler{}
+
+	assert.Equal(t, []zap.Field{
+		zap.String("key", "value"),
+		zap.String("traceId", "abc"),
+		zap.String("spanId", "def"),
+		zap.Namespace("ns"),
+		zap.Object("obj", obj),
+	}, fieldenc.Expand([]zap.Field{
+		zap.String("key", "value"),
+		zap.Skip(),
+		zap.Inline(obj),
+		zap.Namespace("ns"),
+		zap.Object("obj", obj),
+	}), "fields should be expanded")
+}
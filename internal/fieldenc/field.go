@@ -0,0 +1,141 @@
+package fieldenc
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldEncoder is a zapcore.ObjectEncoder that records the encoded fields as
+// keyed zap.Field. Objects and arrays are kept as is to be serialized lazily
+// and namespaces are recorded as zap.Namespace.
+type fieldEncoder struct {
+	fields []zap.Field
+}
+
+var _ zapcore.ObjectEncoder = (*fieldEncoder)(nil)
+
+// Expand returns the keyed fields encoded by the fields, which expands inline
+// fields into the keys they add and removes skipped fields.
+func Expand(fields []zap.Field) []zap.Field {
+	enc := &fieldEncoder{
+		fields: make([]zap.Field, 0, len(fields)),
+	}
+
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	return enc.fields
+}
+
+func (enc *fieldEncoder) add(f zap.Field) {
+	enc.fields = append(enc.fields, f)
+}
+
+func (enc *fieldEncoder) AddArray(key string, ms zapcore.ArrayMarshaler) error {
+	enc.add(zap.Array(key, ms))
+
+	return nil
+}
+
+func (enc *fieldEncoder) AddObject(key string, ms zapcore.ObjectMarshaler) error {
+	enc.add(zap.Object(key, ms))
+
+	return nil
+}
+
+func (enc *fieldEncoder) AddBinary(key string, b []byte) {
+	enc.add(zap.Binary(key, b))
+}
+
+func (enc *fieldEncoder) AddByteString(key string, b []byte) {
+	enc.add(zap.ByteString(key, b))
+}
+
+func (enc *fieldEncoder) AddBool(key string, b bool) {
+	enc.add(zap.Bool(key, b))
+}
+
+func (enc *fieldEncoder) AddComplex128(key string, c complex128) {
+	enc.add(zap.Complex128(key, c))
+}
+
+func (enc *fieldEncoder) AddComplex64(key string, c complex64) {
+	enc.add(zap.Complex64(key, c))
+}
+
+func (enc *fieldEncoder) AddDuration(key string, d time.Duration) {
+	enc.add(zap.Duration(key, d))
+}
+
+func (enc *fieldEncoder) AddFloat64(key string, f float64) {
+	enc.add(zap.Float64(key, f))
+}
+
+func (enc *fieldEncoder) AddFloat32(key string, f float32) {
+	enc.add(zap.Float32(key, f))
+}
+
+func (enc *fieldEncoder) AddInt(key string, i int) {
+	enc.add(zap.Int(key, i))
+}
+
+func (enc *fieldEncoder) AddInt64(key string, i int64) {
+	enc.add(zap.Int64(key, i))
+}
+
+func (enc *fieldEncoder) AddInt32(key string, i int32) {
+	enc.add(zap.Int32(key, i))
+}
+
+func (enc *fieldEncoder) AddInt16(key string, i int16) {
+	enc.add(zap.Int16(key, i))
+}
+
+func (enc *fieldEncoder) AddInt8(key string, i int8) {
+	enc.add(zap.Int8(key, i))
+}
+
+func (enc *fieldEncoder) AddString(key, value string) {
+	enc.add(zap.String(key, value))
+}
+
+func (enc *fieldEncoder) AddTime(key string, t time.Time) {
+	enc.add(zap.Time(key, t))
+}
+
+func (enc *fieldEncoder) AddUint(key string, u uint) {
+	enc.add(zap.Uint(key, u))
+}
+
+func (enc *fieldEncoder) AddUint64(key string, u uint64) {
+	enc.add(zap.Uint64(key, u))
+}
+
+func (enc *fieldEncoder) AddUint32(key string, u uint32) {
+	enc.add(zap.Uint32(key, u))
+}
+
+func (enc *fieldEncoder) AddUint16(key string, u uint16) {
+	enc.add(zap.Uint16(key, u))
+}
+
+func (enc *fieldEncoder) AddUint8(key string, u uint8) {
+	enc.add(zap.Uint8(key, u))
+}
+
+func (enc *fieldEncoder) AddUintptr(key string, u uintptr) {
+	enc.add(zap.Uintptr(key, u))
+}
+
+func (enc *fieldEncoder) AddReflected(key string, v interface{}) error {
+	enc.add(zap.Reflect(key, v))
+
+	return nil
+}
+
+func (enc *fieldEncoder) OpenNamespace(key string) {
+	enc.add(zap.Namespace(key))
+}
@@ -0,0 +1,71 @@
+// Package fieldenc provides the zapcore.ObjectEncoder building blocks shared
+// by the packages converting zap fields into other representations.
+package fieldenc
+
+// KeyValue is an encoded field with its converted value.
+type KeyValue[V any] struct {
+	Key   string
+	Value V
+}
+
+// Namespace collects the encoded fields into the innermost namespace opened
+// with Open, as zapcore.ObjectEncoder.OpenNamespace nests every field added
+// after it.
+type Namespace[V any] struct {
+	kvs []KeyValue[V]
+
+	// inner holds the fields added after Open is called.
+	inner *Namespace[V]
+	key   string
+}
+
+func (ns *Namespace[V]) innermost() *Namespace[V] {
+	for ns.inner != nil {
+		ns = ns.inner
+	}
+
+	return ns
+}
+
+// Add adds a field to the innermost namespace.
+func (ns *Namespace[V]) Add(key string, v V) {
+	ns = ns.innermost()
+	ns.kvs = append(ns.kvs, KeyValue[V]{Key: key, Value: v})
+}
+
+// Open opens a namespace inside the innermost namespace.
+func (ns *Namespace[V]) Open(key string) {
+	ns = ns.innermost()
+	ns.inner = &Namespace[V]{}
+	ns.key = key
+}
+
+// KeyValues returns the collected fields. The open namespaces are converted
+// into the value of their key with group, even if they hold no field.
+func (ns *Namespace[V]) KeyValues(group func(kvs []KeyValue[V]) V) []KeyValue[V] {
+	if ns.inner == nil {
+		return ns.kvs
+	}
+
+	kvs := make([]KeyValue[V], len(ns.kvs), len(ns.kvs)+1)
+	copy(kvs, ns.kvs)
+
+	return append(kvs, KeyValue[V]{
+		Key:   ns.key,
+		Value: group(ns.inner.KeyValues(group)),
+	})
+}
+
+// Clone returns a copy of the namespace that does not share its fields.
+func (ns *Namespace[V]) Clone() *Namespace[V] {
+	clone := &Namespace[V]{
+		kvs: ns.kvs[:len(ns.kvs):len(ns.kvs)],
+		key: ns.key,
+	}
+
+	if ns.inner != nil {
+		clone.inner = ns.inner.Clone()
+	}
+
+	return clone
+}
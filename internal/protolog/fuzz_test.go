@@ -0,0 +1,155 @@
+package protolog_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/internal/protolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// newGroupDescriptor builds a proto2 message descriptor with a group, a closed
+// enum and a recursive field, which are not covered by the generated messages.
+func newGroupDescriptor(f *testing.F) protoreflect.MessageDescriptor {
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("zapf/fuzz.proto"),
+		Package: proto.String("zapf.fuzz"),
+		Syntax:  proto.String("proto2"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Kind"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("KIND_ZERO"), Number: proto.Int32(0)},
+				{Name: proto.String("KIND_ONE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Node"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:     proto.String("group"),
+					Number:   proto.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_GROUP.Enum(),
+					TypeName: proto.String(".zapf.fuzz.Node.Group"),
+				},
+				{
+					Name:     proto.String("kinds"),
+					Number:   proto.Int32(2),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+					TypeName: proto.String(".zapf.fuzz.Kind"),
+				},
+				{
+					Name:     proto.String("children"),
+					Number:   proto.Int32(3),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".zapf.fuzz.Node"),
+				},
+				{
+					Name:   proto.String("number"),
+					Number: proto.Int32(4),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_SINT64.Enum(),
+				},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Group"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:   proto.String("text"),
+					Number: proto.Int32(1),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				}},
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	require.NoError(f, err, "fuzz descriptor should be valid")
+
+	return fd.Messages().ByName("Node")
+}
+
+// unmarshal unmarshals the message, returning the panics of dynamicpb on some
+// malformed inputs as errors so the fuzz target only reports marshaler
+// failures.
+func unmarshal(b []byte, msg proto.Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unmarshal panic: %v", r)
+		}
+	}()
+
+	return proto.Unmarshal(b, msg)
+}
+
+func FuzzOptions_MarshalerOf(f *testing.F) {
+	descs := []protoreflect.MessageDescriptor{
+		(&marshalerpb.Marshaler{}).ProtoReflect().Descriptor(),
+		(&structpb.Struct{}).ProtoReflect().Descriptor(),
+		(&descriptorpb.FileDescriptorSet{}).ProtoReflect().Descriptor(),
+		newGroupDescriptor(f),
+	}
+
+	anyPayload, err := anypb.New(&marshalerpb.Message{Text: "hello"})
+	require.NoError(f, err, "anypb new must return no error")
+
+	st, err := structpb.NewStruct(map[string]interface{}{
+		"list":   []interface{}{1, "two", nil, true},
+		"nested": map[string]interface{}{"key": "value"},
+	})
+	require.NoError(f, err, "structpb new struct must return no error")
+
+	for i, msg := range []proto.Message{
+		&marshalerpb.Marshaler{
+			Map:   map[string]string{"hello": "world"},
+			Array: []string{"hello"},
+			Bytes: []byte("world"),
+			Enum:  marshalerpb.Choice(99),
+			Payload: &marshalerpb.Marshaler_Any{
+				Any: anyPayload,
+			},
+		},
+		st,
+		protodesc.ToFileDescriptorProto(descs[3].ParentFile()),
+	} {
+		b, err := proto.Marshal(msg)
+		require.NoError(f, err, "seed message should be marshaled")
+
+		f.Add(uint8(i), b)
+	}
+
+	f.Add(uint8(0), []byte{0x9a, 0x01, 0x04, 0x0a, 0x00, 0x12, 0x00})
+	f.Add(uint8(3), []byte{0x0b, 0x0a, 0x02, 'h', 'i', 0x0c, 0x10, 0x63, 0x1a, 0x02, 0x20, 0x03})
+
+	f.Fuzz(func(t *testing.T, i uint8, b []byte) {
+		msg := dynamicpb.NewMessage(descs[int(i)%len(descs)])
+		if err := unmarshal(b, msg); err != nil {
+			t.Skip("input is not a valid message")
+		}
+
+		for _, opts := range []protolog.Options{{}, {Typed: true}} {
+			enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{
+				zap.Object("message", opts.MarshalerOf(msg)),
+			})
+			require.NoError(t, err, "encode entry should return no error")
+
+			assert.True(t, json.Valid(buf.Bytes()), "encoded entry should be valid json: %s", buf.Bytes())
+			assert.NotContains(t, buf.String(), `"messageError"`, "marshaler should not return error")
+		}
+	})
+}
@@ -4,97 +4,63 @@ import (
 	"encoding/base64"
 	"fmt"
 
+	"github.com/adzil/zapf/internal/encoder"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
+// ErrorKey is the object key of the error encountered while marshaling a
+// message. Marshaling never panics nor fails; the error is logged in place of
+// the remaining fields of the innermost message instead.
+const ErrorKey = "_error"
+
 var (
 	anypbAnyFullName = (&anypb.Any{}).ProtoReflect().Descriptor().FullName()
 )
 
-type encoder interface {
-	AppendObject(zapcore.ObjectMarshaler) error
-	AppendArray(zapcore.ArrayMarshaler) error
-	AppendBool(bool)
-	AppendString(string)
-	AppendFloat32(float32)
-	AppendFloat64(float64)
-	AppendInt32(int32)
-	AppendUint32(uint32)
-	AppendInt64(int64)
-	AppendUint64(uint64)
-}
-
-type fieldEncoder struct {
-	enc zapcore.ObjectEncoder
-	key string
-}
-
-func (e fieldEncoder) AppendObject(ms zapcore.ObjectMarshaler) error {
-	return e.enc.AddObject(e.key, ms)
-}
-
-func (e fieldEncoder) AppendArray(ms zapcore.ArrayMarshaler) error {
-	return e.enc.AddArray(e.key, ms)
-}
-
-func (e fieldEncoder) AppendBool(b bool) {
-	e.enc.AddBool(e.key, b)
-}
-
-func (e fieldEncoder) AppendString(s string) {
-	e.enc.AddString(e.key, s)
-}
-
-func (e fieldEncoder) AppendFloat32(f float32) {
-	e.enc.AddFloat32(e.key, f)
-}
-
-func (e fieldEncoder) AppendFloat64(f float64) {
-	e.enc.AddFloat64(e.key, f)
+// Resolver resolves the message types of google.protobuf.Any messages and
+// their extensions.
+type Resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
 }
 
-func (e fieldEncoder) AppendInt32(i int32) {
-	e.enc.AddInt32(e.key, i)
-}
-
-func (e fieldEncoder) AppendUint32(u uint32) {
-	e.enc.AddUint32(e.key, u)
-}
-
-func (e fieldEncoder) AppendInt64(i int64) {
-	e.enc.AddInt64(e.key, i)
-}
-
-func (e fieldEncoder) AppendUint64(u uint64) {
-	e.enc.AddUint64(e.key, u)
+// recoverError converts a recovered panic value into an error.
+func recoverError(r interface{}, err *error) {
+	if r != nil {
+		*err = fmt.Errorf("panic while marshaling protobuf: %v", r)
+	}
 }
 
-func appendField[E encoder](enc E, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+func appendField[E encoder.Encoder](enc E, r Resolver, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
 	switch {
 	case fd.IsMap():
 		return enc.AppendObject(mapMarshaler{
+			Resolver:  r,
 			ValueDesc: fd.MapValue(),
 			Map:       v.Map(),
 		})
 
 	case fd.IsList():
 		return enc.AppendArray(listMarshaler{
-			Desc: fd,
-			List: v.List(),
+			Resolver: r,
+			Desc:     fd,
+			List:     v.List(),
 		})
 	}
 
-	return appendValue(enc, fd, v)
+	return appendValue(enc, r, fd, v)
 }
 
-func appendValue[E encoder](enc E, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+func appendValue[E encoder.Encoder](enc E, r Resolver, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
 	switch fd.Kind() {
-	case protoreflect.MessageKind:
+	case protoreflect.MessageKind, protoreflect.GroupKind:
 		return enc.AppendObject(messageMarshaler{
-			Message: v.Message(),
+			Resolver: r,
+			Message:  v.Message(),
 		})
 
 	case protoreflect.BoolKind:
@@ -104,8 +70,15 @@ func appendValue[E encoder](enc E, fd protoreflect.FieldDescriptor, v protorefle
 		enc.AppendString(base64.StdEncoding.EncodeToString(v.Bytes()))
 
 	case protoreflect.EnumKind:
-		enumName := fd.Enum().Values().ByNumber(v.Enum()).Name()
-		enc.AppendString(string(enumName))
+		// Unknown enum numbers are valid in open enums.
+		enumValue := fd.Enum().Values().ByNumber(v.Enum())
+		if enumValue == nil {
+			enc.AppendInt32(int32(v.Enum()))
+
+			break
+		}
+
+		enc.AppendString(string(enumValue.Name()))
 
 	case protoreflect.FloatKind:
 		enc.AppendFloat32(float32(v.Float()))
@@ -136,13 +109,16 @@ func appendValue[E encoder](enc E, fd protoreflect.FieldDescriptor, v protorefle
 }
 
 type listMarshaler struct {
-	Desc protoreflect.FieldDescriptor
-	List protoreflect.List
+	Resolver Resolver
+	Desc     protoreflect.FieldDescriptor
+	List     protoreflect.List
 }
 
-func (m listMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+func (m listMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) (err error) {
+	defer func() { recoverError(recover(), &err) }()
+
 	for i := 0; i < m.List.Len(); i++ {
-		if err := appendValue(enc, m.Desc, m.List.Get(i)); err != nil {
+		if err := appendValue(enc, m.Resolver, m.Desc, m.List.Get(i)); err != nil {
 			return err
 		}
 	}
@@ -151,13 +127,16 @@ func (m listMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
 }
 
 type mapMarshaler struct {
+	Resolver  Resolver
 	ValueDesc protoreflect.FieldDescriptor
 	Map       protoreflect.Map
 }
 
 func (m mapMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
+	defer func() { recoverError(recover(), &err) }()
+
 	m.Map.Range(func(mk protoreflect.MapKey, v protoreflect.Value) bool {
-		err = appendValue(fieldEncoder{enc, mk.String()}, m.ValueDesc, v)
+		err = appendValue(encoder.Field(enc, mk.String()), m.Resolver, m.ValueDesc, v)
 
 		return err == nil
 	})
@@ -166,28 +145,43 @@ func (m mapMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
 }
 
 type messageMarshaler struct {
-	Typed   bool
-	Message protoreflect.Message
+	Typed    bool
+	Resolver Resolver
+	Message  protoreflect.Message
 }
 
 func (m *messageMarshaler) marshalAny(enc zapcore.ObjectEncoder) error {
-	anyMsg, ok := m.Message.Interface().(interface{ UnmarshalNew() (proto.Message, error) })
-	if !ok {
-		return fmt.Errorf("message %s does not implement UnmarshalNew method", anypbAnyFullName)
+	// Copy the fields by name to support dynamic messages as well.
+	fields := m.Message.Descriptor().Fields()
+	anyMsg := &anypb.Any{
+		TypeUrl: m.Message.Get(fields.ByName("type_url")).String(),
+		Value:   m.Message.Get(fields.ByName("value")).Bytes(),
 	}
 
-	v, err := anyMsg.UnmarshalNew()
+	v, err := anypb.UnmarshalNew(anyMsg, proto.UnmarshalOptions{
+		Resolver: m.Resolver,
+	})
 	if err != nil {
 		return err
 	}
 
 	return messageMarshaler{
-		Typed:   true,
-		Message: v.ProtoReflect(),
+		Typed:    true,
+		Resolver: m.Resolver,
+		Message:  v.ProtoReflect(),
 	}.MarshalLogObject(enc)
 }
 
 func (m messageMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
+	defer func() {
+		recoverError(recover(), &err)
+
+		if err != nil {
+			enc.AddString(ErrorKey, err.Error())
+			err = nil
+		}
+	}()
+
 	fullName := m.Message.Descriptor().FullName()
 
 	if fullName == anypbAnyFullName {
@@ -201,7 +195,7 @@ func (m messageMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (err error
 	m.Message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
 		key := fd.JSONName()
 
-		err = appendField(fieldEncoder{enc, key}, fd, v)
+		err = appendField(encoder.Field(enc, key), m.Resolver, fd, v)
 
 		return err == nil
 	})
@@ -217,8 +211,13 @@ func (fn objectMarshalerFunc) MarshalLogObject(enc zapcore.ObjectEncoder) error
 
 type Options struct {
 	Typed bool
+	// Resolver resolves the types of google.protobuf.Any messages.
+	// protoregistry.GlobalTypes is used when it is nil.
+	Resolver Resolver
 }
 
+// MarshalerOf returns a lazy object marshaler of a protobuf message. The
+// marshaler never returns an error; see ErrorKey.
 func (opts Options) MarshalerOf(msg proto.Message) zapcore.ObjectMarshaler {
 	if msg == nil {
 		return objectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
@@ -227,11 +226,14 @@ func (opts Options) MarshalerOf(msg proto.Message) zapcore.ObjectMarshaler {
 	}
 
 	return messageMarshaler{
-		Typed:   opts.Typed,
-		Message: msg.ProtoReflect(),
+		Typed:    opts.Typed,
+		Resolver: opts.Resolver,
+		Message:  msg.ProtoReflect(),
 	}
 }
 
+// MarshalerOf returns a lazy object marshaler of a protobuf message with the
+// default options.
 func MarshalerOf(msg proto.Message) zapcore.ObjectMarshaler {
 	return Options{}.MarshalerOf(msg)
 }
@@ -4,9 +4,9 @@ import (
 	"encoding/base64"
 	"testing"
 
-	rec "github.com/adzil/zapf/internal/fieldrecorder"
 	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
 	"github.com/adzil/zapf/internal/protolog"
+	"github.com/adzil/zapf/zaprec"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -17,8 +17,7 @@ func TestOptions_MarshalerOf_MarshalLogObject(t *testing.T) {
 	type Context struct {
 		Input        proto.Message
 		Options      protolog.Options
-		AssertErr    func(error)
-		AssertObject func(rec.Object)
+		AssertObject func(zaprec.Object)
 	}
 
 	for k, v := range map[string]func(t *testing.T, tc *Context){
@@ -51,33 +50,33 @@ func TestOptions_MarshalerOf_MarshalLogObject(t *testing.T) {
 				},
 			}
 
-			expected := rec.Object{
-				"map": rec.Object{
-					"hello": rec.String("world"),
+			expected := zaprec.Object{
+				"map": zaprec.Object{
+					"hello": zaprec.String("world"),
 				},
-				"array":    rec.Array{rec.String("hello"), rec.String("world")},
-				"bool":     rec.Bool(true),
-				"string":   rec.String("hello"),
-				"bytes":    rec.String(base64.StdEncoding.EncodeToString([]byte(`world`))),
-				"enum":     rec.String(marshalerpb.Choice_CHOICE_ONE.String()),
-				"float":    rec.Float32(1.0),
-				"double":   rec.Float64(-2.0),
-				"int32":    rec.Int32(-3),
-				"int64":    rec.Int64(-4),
-				"uint32":   rec.Uint32(5),
-				"uint64":   rec.Uint64(6),
-				"sint32":   rec.Int32(-7),
-				"sint64":   rec.Int64(-8),
-				"fixed32":  rec.Uint32(9),
-				"fixed64":  rec.Uint64(10),
-				"sfixed32": rec.Int32(-11),
-				"sfixed64": rec.Int64(-12),
-				"message": rec.Object{
-					"text": rec.String("hello"),
+				"array":    zaprec.Array{zaprec.String("hello"), zaprec.String("world")},
+				"bool":     zaprec.Bool(true),
+				"string":   zaprec.String("hello"),
+				"bytes":    zaprec.String(base64.StdEncoding.EncodeToString([]byte(`world`))),
+				"enum":     zaprec.String(marshalerpb.Choice_CHOICE_ONE.String()),
+				"float":    zaprec.Float32(1.0),
+				"double":   zaprec.Float64(-2.0),
+				"int32":    zaprec.Int32(-3),
+				"int64":    zaprec.Int64(-4),
+				"uint32":   zaprec.Uint32(5),
+				"uint64":   zaprec.Uint64(6),
+				"sint32":   zaprec.Int32(-7),
+				"sint64":   zaprec.Int64(-8),
+				"fixed32":  zaprec.Uint32(9),
+				"fixed64":  zaprec.Uint64(10),
+				"sfixed32": zaprec.Int32(-11),
+				"sfixed64": zaprec.Int64(-12),
+				"message": zaprec.Object{
+					"text": zaprec.String("hello"),
 				},
 			}
 
-			tc.AssertObject = func(o rec.Object) {
+			tc.AssertObject = func(o zaprec.Object) {
 				assert.Equal(t, expected, o, "object should reflect filled fields")
 			}
 		},
@@ -94,23 +93,59 @@ func TestOptions_MarshalerOf_MarshalLogObject(t *testing.T) {
 				},
 			}
 
-			expected := rec.Object{
-				"any": rec.Object{
-					"@type": rec.String(anyPayload.TypeUrl),
-					"text":  rec.String("hello"),
+			expected := zaprec.Object{
+				"any": zaprec.Object{
+					"@type": zaprec.String(anyPayload.TypeUrl),
+					"text":  zaprec.String("hello"),
 				},
 			}
 
-			tc.AssertObject = func(o rec.Object) {
+			tc.AssertObject = func(o zaprec.Object) {
 				assert.Equal(t, expected, o, "object should contain any")
 			}
 		},
 
+		"message with unknown enum number": func(t *testing.T, tc *Context) {
+			tc.Input = &marshalerpb.Marshaler{
+				Enum: marshalerpb.Choice(99),
+			}
+
+			expected := zaprec.Object{
+				"enum": zaprec.Int32(99),
+			}
+
+			tc.AssertObject = func(o zaprec.Object) {
+				assert.Equal(t, expected, o, "unknown enum number should be encoded as number")
+			}
+		},
+
 		"failed unmarshal empty any": func(t *testing.T, tc *Context) {
 			tc.Input = &anypb.Any{}
 
-			tc.AssertErr = func(err error) {
-				assert.ErrorContains(t, err, "empty type URL", "marshal with empty anypb.Any should return error")
+			tc.AssertObject = func(o zaprec.Object) {
+				require.Contains(t, o, protolog.ErrorKey, "marshal with empty anypb.Any should contain error")
+				assert.Contains(t, string(o[protolog.ErrorKey].(zaprec.String)), "empty type URL", "error should describe empty type url")
+			}
+		},
+
+		"failed unmarshal nested invalid any": func(t *testing.T, tc *Context) {
+			tc.Input = &marshalerpb.Marshaler{
+				String_: "hello",
+				Payload: &marshalerpb.Marshaler_Any{
+					Any: &anypb.Any{
+						TypeUrl: "type.googleapis.com/zapf.marshaler.Message",
+						Value:   []byte{0xff},
+					},
+				},
+			}
+
+			tc.AssertObject = func(o zaprec.Object) {
+				assert.Equal(t, zaprec.String("hello"), o["string"], "sibling fields should be encoded")
+				assert.NotContains(t, o, protolog.ErrorKey, "error should not propagate to parent message")
+
+				anyObj, ok := o["any"].(zaprec.Object)
+				require.True(t, ok, "any should be encoded as object")
+				assert.Contains(t, anyObj, protolog.ErrorKey, "any should contain error")
 			}
 		},
 	} {
@@ -118,26 +153,17 @@ func TestOptions_MarshalerOf_MarshalLogObject(t *testing.T) {
 			tc := &Context{}
 			v(t, tc)
 
-			enc := rec.NewObjectEncoder(t)
+			enc := zaprec.NewObjectEncoder()
 			err := tc.Options.MarshalerOf(tc.Input).MarshalLogObject(enc)
 
-			switch {
-			case tc.AssertErr != nil && tc.AssertObject == nil:
-				tc.AssertErr(err)
-
-			case tc.AssertObject != nil:
-				require.NoError(t, err, "marshal log object should return no error")
-				tc.AssertObject(enc.Result())
-
-			default:
-				t.Error("AssertErr and AssertObj cannot be both empty nor both defined")
-			}
+			require.NoError(t, err, "marshal log object should return no error")
+			tc.AssertObject(enc.Result())
 		})
 	}
 }
 
 func TestNewMarshalerOf_MarshalLogObject(t *testing.T) {
-	enc := rec.NewObjectEncoder(t)
+	enc := zaprec.NewObjectEncoder()
 	err := protolog.MarshalerOf(nil).MarshalLogObject(nil)
 
 	assert.NoError(t, err, "new marshaler's marshal log object should return no error")
@@ -0,0 +1,70 @@
+// Package statuslog serializes gRPC statuses for zapgrpc and zaperr. It only
+// depends on the status fields, so the root module does not require gRPC.
+package statuslog
+
+import (
+	"encoding/base64"
+
+	"github.com/adzil/zapf/internal/protolog"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// unresolvedAnyMarshaler serializes an anypb.Any whose message type is not
+// registered with its type URL and base64-encoded value.
+type unresolvedAnyMarshaler struct {
+	Any *anypb.Any
+}
+
+func (m unresolvedAnyMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("@type", m.Any.GetTypeUrl())
+	enc.AddString("value", base64.StdEncoding.EncodeToString(m.Any.GetValue()))
+
+	return nil
+}
+
+type detailsMarshaler []*anypb.Any
+
+func (m detailsMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	opts := protolog.Options{
+		Typed: true,
+	}
+
+	for _, detail := range m {
+		msg, err := detail.UnmarshalNew()
+		if err != nil {
+			if err := enc.AppendObject(unresolvedAnyMarshaler{
+				Any: detail,
+			}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := enc.AppendObject(opts.MarshalerOf(msg)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Marshaler serializes a gRPC status with its code name, message and each
+// detail message with its type URL.
+type Marshaler struct {
+	Code    string
+	Message string
+	Details []*anypb.Any
+}
+
+func (m Marshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("code", m.Code)
+	enc.AddString("message", m.Message)
+
+	if len(m.Details) == 0 {
+		return nil
+	}
+
+	return enc.AddArray("details", detailsMarshaler(m.Details))
+}
@@ -0,0 +1,54 @@
+// Package wrapcore checks the zapcore.Core wrappers that rewrite the entries
+// before writing them to the wrapped core.
+package wrapcore
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Rewriter is a zapcore.Core wrapping another core, which rewrites the entries
+// before writing them to the wrapped core.
+type Rewriter interface {
+	zapcore.Core
+
+	// Rewrite returns the entry and fields to be written to the wrapped core.
+	Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field)
+}
+
+// Check checks the entry with the core wrapped by c and adds a core to ce that
+// writes the entries rewritten by c only to the wrapped cores selected by the
+// check. Unlike writing to the wrapped core, this keeps its sampling and the
+// level of each core of a zapcore.NewTee.
+func Check(c Rewriter, wrapped zapcore.Core, ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := wrapped.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+
+	cc := &checkedCore{
+		Rewriter: c,
+		inner:    inner,
+	}
+
+	ce = ce.AddCore(ent, cc)
+	cc.outer = ce
+
+	return ce
+}
+
+// checkedCore writes an entry through the zapcore.CheckedEntry of the wrapped
+// cores. The write errors are reported to the error output of the outer
+// zapcore.CheckedEntry, which is set by zap.Logger after the entry is checked.
+type checkedCore struct {
+	Rewriter
+	inner *zapcore.CheckedEntry
+	outer *zapcore.CheckedEntry
+}
+
+func (c *checkedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.inner.Entry, fields = c.Rewrite(ent, fields)
+	c.inner.ErrorOutput = c.outer.ErrorOutput
+	c.inner.Write(fields...)
+
+	return nil
+}
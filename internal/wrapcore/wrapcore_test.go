@@ -0,0 +1,87 @@
+package wrapcore_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/adzil/zapf/internal/wrapcore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// prefixCore rewrites the entry messages with a prefix.
+type prefixCore struct {
+	zapcore.Core
+}
+
+func (c prefixCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return wrapcore.Check(c, c.Core, ent, ce)
+}
+
+func (c prefixCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(c.Rewrite(ent, fields))
+}
+
+func (c prefixCore) Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	ent.Message = "rewritten " + ent.Message
+
+	return ent, append(fields, zap.Bool("rewritten", true))
+}
+
+// failingCore fails to write every entry.
+type failingCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c failingCore) With([]zapcore.Field) zapcore.Core {
+	return c
+}
+
+func (c failingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (failingCore) Write(zapcore.Entry, []zapcore.Field) error {
+	return errors.New("write failed")
+}
+
+func (failingCore) Sync() error {
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	infoObs, infoLogs := observer.New(zapcore.InfoLevel)
+	errorObs, errorLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(prefixCore{zapcore.NewTee(infoObs, errorObs)})
+
+	logger.Debug("disabled")
+	logger.Info("hello")
+	logger.Error("failed")
+
+	require.Equal(t, 2, infoLogs.Len(), "info core should write the enabled entries")
+	assert.Equal(t, "rewritten hello", infoLogs.All()[0].Message, "entry should be rewritten")
+	assert.Equal(t, map[string]interface{}{"rewritten": true}, infoLogs.All()[0].ContextMap(),
+		"fields should be rewritten")
+
+	require.Equal(t, 1, errorLogs.Len(), "error core should only write the error entry")
+	assert.Equal(t, "rewritten failed", errorLogs.All()[0].Message, "entry should be rewritten")
+}
+
+func TestCheck_WriteError(t *testing.T) {
+	var errOut bytes.Buffer
+
+	logger := zap.New(prefixCore{failingCore{zapcore.InfoLevel}},
+		zap.ErrorOutput(zapcore.AddSync(&errOut)))
+
+	logger.Info("hello")
+
+	assert.Contains(t, errOut.String(), "write failed", "write error should be reported")
+}
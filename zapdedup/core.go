@@ -0,0 +1,308 @@
+// Package zapdedup provides a zapcore.Core that resolves the duplicate keys
+// of the fields added with With and per entry.
+package zapdedup
+
+import (
+	"strconv"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"github.com/adzil/zapf/internal/wrapcore"
+	"github.com/adzil/zapf/zaptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Policy resolves the fields with duplicate keys.
+type Policy int
+
+const (
+	// LastWins keeps the last field of each key.
+	LastWins Policy = iota
+	// FirstWins keeps the first field of each key.
+	FirstWins
+	// Rename keeps every field and renames the duplicate keys by appending
+	// the suffix and the number of the occurrence, e.g. traceId_1.
+	Rename
+)
+
+// DefaultSuffix is the suffix of the renamed keys when Options.Suffix is not
+// set.
+const DefaultSuffix = "_"
+
+// Options configures the zapcore.Core constructed by Options.NewCore.
+type Options struct {
+	// Policy resolves the fields with duplicate keys. LastWins is used when it
+	// is not set.
+	Policy Policy
+	// Suffix is appended to the duplicate keys with the Rename policy.
+	// DefaultSuffix is used when it is not set.
+	Suffix string
+}
+
+func (opts Options) suffix() string {
+	if opts.Suffix == "" {
+		return DefaultSuffix
+	}
+
+	return opts.Suffix
+}
+
+// spanContextField is a span-context inline field, such as zaptrace.Context,
+// with the number of keyed fields it is expanded into.
+type spanContextField struct {
+	Field zap.Field
+	Len   int
+}
+
+// expandedField is a keyed field expanded from the fields added to the core.
+type expandedField struct {
+	zap.Field
+	// Source is the span-context field the field is expanded from, if any.
+	Source *spanContextField
+	// Renamed reports whether the key is renamed by the Rename policy.
+	Renamed bool
+}
+
+// expand returns the keyed fields of the fields. Only inline fields are
+// expanded into the keyed fields they encode, so the other fields keep their
+// type for the wrapped core. The fields expanded from span-context fields keep
+// their source field.
+func expand(fields []zap.Field) []expandedField {
+	result := make([]expandedField, 0, len(fields))
+
+	for _, f := range fields {
+		if f.Type != zapcore.InlineMarshalerType {
+			if f.Type != zapcore.SkipType {
+				result = append(result, expandedField{Field: f})
+			}
+
+			continue
+		}
+
+		expanded := fieldenc.Expand([]zap.Field{f})
+
+		var src *spanContextField
+		if _, ok := zaptrace.SpanContextFromField(f); ok {
+			src = &spanContextField{
+				Field: f,
+				Len:   len(expanded),
+			}
+		}
+
+		for _, ef := range expanded {
+			result = append(result, expandedField{
+				Field:  ef,
+				Source: src,
+			})
+		}
+	}
+
+	return result
+}
+
+// collapse returns the resolved fields with the expanded fields of each
+// span-context field replaced by the field itself when none of them is
+// resolved, so the wrapped core can still find the span context.
+func collapse(fields []expandedField) []zap.Field {
+	result := make([]zap.Field, 0, len(fields))
+
+	for i := 0; i < len(fields); {
+		if src := fields[i].Source; src != nil && intact(src, fields[i:]) {
+			result = append(result, src.Field)
+			i += src.Len
+
+			continue
+		}
+
+		result = append(result, fields[i].Field)
+		i++
+	}
+
+	return result
+}
+
+// intact reports whether the fields start with every field expanded from the
+// span-context field without being renamed.
+func intact(src *spanContextField, fields []expandedField) bool {
+	if len(fields) < src.Len {
+		return false
+	}
+
+	for _, f := range fields[:src.Len] {
+		if f.Source != src || f.Renamed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// prefixes returns the prefixes of the namespaces each field is nested in.
+func prefixes(fields []expandedField) []string {
+	result := make([]string, len(fields))
+
+	prefix := ""
+
+	for i, f := range fields {
+		result[i] = prefix
+
+		if f.Type == zapcore.NamespaceType {
+			prefix += f.Key + "."
+		}
+	}
+
+	return result
+}
+
+// rename returns the key of the nth occurrence of a field that does not
+// collide with the used keys.
+func (opts Options) rename(prefix, key string, n int, used map[string]struct{}) string {
+	for ; ; n++ {
+		renamed := key + opts.suffix() + strconv.Itoa(n)
+		if _, ok := used[prefix+renamed]; !ok {
+			return renamed
+		}
+	}
+}
+
+// resolve returns the fields without duplicate keys within each namespace.
+// The fields are kept in their order. Namespaces are never resolved as they
+// nest the fields after them.
+func (opts Options) resolve(fields []expandedField) []expandedField {
+	prefixes := prefixes(fields)
+	seen := make(map[string]int, len(fields))
+	resolved := make([]expandedField, 0, len(fields))
+
+	switch opts.Policy {
+	case FirstWins:
+		for i, f := range fields {
+			if f.Type != zapcore.NamespaceType {
+				if _, ok := seen[prefixes[i]+f.Key]; ok {
+					continue
+				}
+
+				seen[prefixes[i]+f.Key] = 1
+			}
+
+			resolved = append(resolved, f)
+		}
+
+	case Rename:
+		used := make(map[string]struct{}, len(fields))
+		for i, f := range fields {
+			used[prefixes[i]+f.Key] = struct{}{}
+		}
+
+		for i, f := range fields {
+			if f.Type != zapcore.NamespaceType {
+				n := seen[prefixes[i]+f.Key]
+				seen[prefixes[i]+f.Key]++
+
+				if n > 0 {
+					f.Key = opts.rename(prefixes[i], f.Key, n, used)
+					f.Renamed = true
+					used[prefixes[i]+f.Key] = struct{}{}
+				}
+			}
+
+			resolved = append(resolved, f)
+		}
+
+	default:
+		for i := len(fields) - 1; i >= 0; i-- {
+			f := fields[i]
+
+			if f.Type != zapcore.NamespaceType {
+				if _, ok := seen[prefixes[i]+f.Key]; ok {
+					continue
+				}
+
+				seen[prefixes[i]+f.Key] = 1
+			}
+
+			resolved = append(resolved, f)
+		}
+
+		for i, j := 0, len(resolved)-1; i < j; i, j = i+1, j-1 {
+			resolved[i], resolved[j] = resolved[j], resolved[i]
+		}
+	}
+
+	return resolved
+}
+
+type core struct {
+	zapcore.Core
+	opts Options
+	// fields are the resolved fields added with With. They are also added to
+	// the wrapped core unless the policy is LastWins.
+	fields []expandedField
+}
+
+// NewCore wraps a zapcore.Core to resolve the duplicate keys of the fields
+// added with With and per entry with the policy, including the keys added by
+// inline fields such as zaptrace.Context. The fields added with With are
+// resolved once and added to the wrapped core, except with the LastWins policy
+// where they are kept by the core and written along with each entry as the
+// entry fields may replace them. Span-context fields of zaptrace are written
+// as is when none of their keys is resolved, so the span context is still
+// available to the wrapped core.
+func (opts Options) NewCore(c zapcore.Core) zapcore.Core {
+	return &core{
+		Core: c,
+		opts: opts,
+	}
+}
+
+// NewCore wraps a zapcore.Core with the default options. See Options.NewCore
+// for details.
+func NewCore(c zapcore.Core) zapcore.Core {
+	return Options{}.NewCore(c)
+}
+
+func (c *core) merge(fields []zapcore.Field) []expandedField {
+	expanded := expand(fields)
+
+	merged := make([]expandedField, 0, len(c.fields)+len(expanded))
+	merged = append(merged, c.fields...)
+
+	return append(merged, expanded...)
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	resolved := c.opts.resolve(c.merge(fields))
+
+	if c.opts.Policy == LastWins {
+		return &core{
+			Core:   c.Core,
+			opts:   c.opts,
+			fields: resolved,
+		}
+	}
+
+	// The other policies never change the fields resolved before, so only the
+	// new fields are added to the wrapped core.
+	return &core{
+		Core:   c.Core.With(collapse(resolved[len(c.fields):])),
+		opts:   c.opts,
+		fields: resolved,
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return wrapcore.Check(c, c.Core, ent, ce)
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(c.Rewrite(ent, fields))
+}
+
+// Rewrite returns the entry with the duplicate keys of the fields resolved.
+func (c *core) Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	resolved := c.opts.resolve(c.merge(fields))
+	if c.opts.Policy != LastWins {
+		resolved = resolved[len(c.fields):]
+	}
+
+	return ent, collapse(resolved)
+}
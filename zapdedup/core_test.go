@@ -0,0 +1,308 @@
+package zapdedup_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zapdedup"
+	"github.com/adzil/zapf/zapecs"
+	"github.com/adzil/zapf/zapgcp"
+	"github.com/adzil/zapf/zapotel"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// traceMarshaler mimics the inline trace context fields of zaptrace.
+type traceMarshaler string
+
+func (m traceMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("traceId", string(m))
+
+	return nil
+}
+
+// recordingProcessor records the emitted log records.
+type recordingProcessor struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (p *recordingProcessor) OnEmit(_ context.Context, record sdklog.Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.records = append(p.records, record.Clone())
+
+	return nil
+}
+
+func (p *recordingProcessor) Enabled(context.Context, sdklog.Record) bool {
+	return true
+}
+
+func (p *recordingProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *recordingProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p *recordingProcessor) Records() []sdklog.Record {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.records
+}
+
+func decodeEntry(t *testing.T, out *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry), "entry should be valid json")
+
+	return entry
+}
+
+func TestOptions_NewCore(t *testing.T) {
+	type Context struct {
+		Options zapdedup.Options
+		Log     func(logger *zap.Logger)
+		Expects string
+	}
+
+	log := func(logger *zap.Logger) {
+		logger.With(zap.Inline(traceMarshaler("a")), zap.String("key", "a")).
+			Info("test", zap.Inline(traceMarshaler("b")), zap.Int("count", 1))
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with last wins": func(t *testing.T, tc *Context) {
+			tc.Log = log
+
+			tc.Expects = `{"msg":"test","key":"a","traceId":"b","count":1}` + "\n"
+		},
+
+		"with first wins": func(t *testing.T, tc *Context) {
+			tc.Options.Policy = zapdedup.FirstWins
+			tc.Log = log
+
+			tc.Expects = `{"msg":"test","traceId":"a","key":"a","count":1}` + "\n"
+		},
+
+		"with rename": func(t *testing.T, tc *Context) {
+			tc.Options.Policy = zapdedup.Rename
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zap.String("key", "a"), zap.String("key_1", "b")).
+					Info("test", zap.String("key", "c"), zap.String("key", "d"))
+			}
+
+			tc.Expects = `{"msg":"test","key":"a","key_1":"b","key_2":"c","key_3":"d"}` + "\n"
+		},
+
+		"with rename in nested with": func(t *testing.T, tc *Context) {
+			tc.Options.Policy = zapdedup.Rename
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zap.String("key", "a")).With(zap.String("key", "b")).
+					Info("test", zap.String("key", "c"))
+			}
+
+			tc.Expects = `{"msg":"test","key":"a","key_1":"b","key_2":"c"}` + "\n"
+		},
+
+		"with rename suffix": func(t *testing.T, tc *Context) {
+			tc.Options.Policy = zapdedup.Rename
+			tc.Options.Suffix = "#"
+			tc.Log = log
+
+			tc.Expects = `{"msg":"test","traceId":"a","key":"a","traceId#1":"b","count":1}` + "\n"
+		},
+
+		"with namespace": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zap.String("key", "a"), zap.Namespace("ns"), zap.String("key", "b")).
+					Info("test", zap.String("key", "c"), zap.Skip())
+			}
+
+			tc.Expects = `{"msg":"test","key":"a","ns":{"key":"c"}}` + "\n"
+		},
+
+		"with lazy object": func(t *testing.T, tc *Context) {
+			calls := 0
+			tc.Log = func(logger *zap.Logger) {
+				logger = logger.With(zap.Object("obj", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					calls++
+					enc.AddInt("calls", calls)
+
+					return nil
+				})))
+
+				logger.Debug("skipped")
+				logger.Info("test")
+			}
+
+			tc.Expects = `{"msg":"test","obj":{"calls":1}}` + "\n"
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			var out bytes.Buffer
+
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+				MessageKey: "msg",
+			})
+			core := tc.Options.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.InfoLevel))
+			tc.Log(zap.New(core))
+
+			assert.Equal(t, tc.Expects, out.String(), "output should match")
+		})
+	}
+}
+
+func TestNewCore_SpanContext(t *testing.T) {
+	type Context struct {
+		Core         zapcore.Core
+		Fields       []zap.Field
+		AssertResult func(spanCtx trace.SpanContext, sr *tracetest.SpanRecorder)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with zapgcp": func(t *testing.T, tc *Context) {
+			var out bytes.Buffer
+
+			enc := zapcore.NewJSONEncoder(zapgcp.EncoderConfig())
+			tc.Core = zapgcp.Options{ProjectID: "test-project"}.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+
+			tc.AssertResult = func(spanCtx trace.SpanContext, _ *tracetest.SpanRecorder) {
+				entry := decodeEntry(t, &out)
+				assert.Equal(t, "projects/test-project/traces/"+spanCtx.TraceID().String(), entry[zapgcp.TraceKey], "trace should match")
+				assert.Equal(t, spanCtx.SpanID().String(), entry[zapgcp.SpanIDKey], "span id should match")
+				assert.NotContains(t, entry, "traceId", "trace id should not be written as is")
+				assert.Equal(t, "b", entry["key"], "duplicate key should be resolved")
+			}
+		},
+
+		"with zapecs": func(t *testing.T, tc *Context) {
+			var out bytes.Buffer
+
+			enc := zapcore.NewJSONEncoder(zapecs.EncoderConfig())
+			tc.Core = zapecs.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+			tc.Fields = []zap.Field{zap.Error(errors.New("boom"))}
+
+			tc.AssertResult = func(spanCtx trace.SpanContext, _ *tracetest.SpanRecorder) {
+				entry := decodeEntry(t, &out)
+				assert.Equal(t, spanCtx.TraceID().String(), entry[zapecs.TraceIDKey], "trace id should match")
+				assert.Equal(t, spanCtx.SpanID().String(), entry[zapecs.SpanIDKey], "span id should match")
+				assert.NotContains(t, entry, "traceId", "trace id should not be written as is")
+				assert.Equal(t, "b", entry["key"], "duplicate key should be resolved")
+				assert.Equal(t, map[string]interface{}{
+					"message": "boom",
+					"type":    "*errors.errorString",
+				}, entry[zapecs.ErrorKey], "error should be written as an error object")
+			}
+		},
+
+		"with zapotel": func(t *testing.T, tc *Context) {
+			p := &recordingProcessor{}
+
+			tc.Core = zapotel.Options{
+				LoggerProvider: sdklog.NewLoggerProvider(sdklog.WithProcessor(p)),
+			}.NewCore("test")
+
+			tc.AssertResult = func(spanCtx trace.SpanContext, _ *tracetest.SpanRecorder) {
+				require.Len(t, p.Records(), 1, "there should be one record")
+
+				r := p.Records()[0]
+				assert.Equal(t, spanCtx.TraceID(), r.TraceID(), "trace id should match")
+				assert.Equal(t, spanCtx.SpanID(), r.SpanID(), "span id should match")
+
+				var kvs []log.KeyValue
+
+				r.WalkAttributes(func(kv log.KeyValue) bool {
+					kvs = append(kvs, kv)
+
+					return true
+				})
+
+				assert.Equal(t, []log.KeyValue{log.String("key", "b")}, kvs, "duplicate key should be resolved")
+			}
+		},
+
+		"with event core": func(t *testing.T, tc *Context) {
+			obs, logs := observer.New(zapcore.DebugLevel)
+			tc.Core = zaptrace.NewEventCore(obs)
+
+			tc.AssertResult = func(_ trace.SpanContext, sr *tracetest.SpanRecorder) {
+				assert.Equal(t, 1, logs.Len(), "entry should be written to the wrapped core")
+				require.Len(t, sr.Ended(), 1, "there should be one ended span")
+
+				events := sr.Ended()[0].Events()
+				require.Len(t, events, 1, "span should have one event")
+				assert.Equal(t, []attribute.KeyValue{
+					attribute.String("key", "b"),
+				}, events[0].Attributes, "duplicate key should be resolved")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+			ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+
+			zap.New(zapdedup.NewCore(tc.Core)).
+				With(zaptrace.Context(ctx), zap.String("key", "a")).
+				Info("traced", append([]zap.Field{zap.String("key", "b")}, tc.Fields...)...)
+
+			span.End()
+
+			tc.AssertResult(span.SpanContext(), sr)
+		})
+	}
+}
+
+func TestNewCore_Sampler(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	sampler := zapcore.NewSamplerWithOptions(obs, time.Minute, 1, 0)
+	logger := zap.New(zapdedup.NewCore(sampler))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("hello")
+	}
+
+	assert.Equal(t, 1, logs.Len(), "sampled entries should be dropped")
+}
+
+func TestNewCore_Tee(t *testing.T) {
+	infoObs, infoLogs := observer.New(zapcore.InfoLevel)
+	errorObs, errorLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(zapdedup.NewCore(zapcore.NewTee(infoObs, errorObs)))
+
+	logger.Info("hello", zap.String("key", "a"), zap.String("key", "b"))
+	logger.Error("failed", zap.String("key", "a"), zap.String("key", "b"))
+
+	assert.Equal(t, 2, infoLogs.Len(), "info core should write both entries")
+	require.Equal(t, 1, errorLogs.Len(), "error core should only write the error entry")
+	assert.Equal(t, map[string]interface{}{"key": "b"}, errorLogs.All()[0].ContextMap(),
+		"duplicate key should be resolved")
+}
@@ -0,0 +1,30 @@
+module github.com/adzil/zapf/zapdedup
+
+go 1.21
+
+require (
+	github.com/adzil/zapf v0.2.0
+	github.com/adzil/zapf/zapecs v0.2.0
+	github.com/adzil/zapf/zapgcp v0.2.0
+	github.com/adzil/zapf/zapotel v0.2.0
+	github.com/adzil/zapf/zaptrace v0.2.0
+	github.com/stretchr/testify v1.9.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/log v0.4.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/sdk/log v0.4.0
+	go.opentelemetry.io/otel/trace v1.28.0
+	go.uber.org/zap v1.26.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,242 @@
+package zapecs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/adzil/zapf/internal/wrapcore"
+	"github.com/adzil/zapf/zaptrace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Version is the Elastic Common Schema version of the entries.
+const Version = "8.11.0"
+
+// The keys of the Elastic Common Schema fields added by the core.
+const (
+	VersionKey   = "ecs.version"
+	LogOriginKey = "log.origin"
+	ErrorKey     = "error"
+	TraceIDKey   = "trace.id"
+	SpanIDKey    = "span.id"
+)
+
+// Options configures the zapcore.Core constructed by NewCore.
+type Options struct {
+	// Namespace prefixes the keys of object and array fields, such as Protobuf
+	// messages constructed with zapproto, to keep their nested fields from
+	// conflicting with the Elastic Common Schema mappings. The keys are left as
+	// is when it is not set.
+	Namespace string
+}
+
+type logOriginMarshaler zapcore.EntryCaller
+
+func (m logOriginMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	caller := zapcore.EntryCaller(m)
+	line := strconv.Itoa(caller.Line)
+
+	if err := enc.AddObject("file", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("name", strings.TrimSuffix(caller.TrimmedPath(), ":"+line))
+		enc.AddInt("line", caller.Line)
+
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	if caller.Function != "" {
+		enc.AddString("function", caller.Function)
+	}
+
+	return nil
+}
+
+type errorMarshaler struct {
+	Error error
+	// Stack is the entry stack trace, which is used when the error does not
+	// carry its own.
+	Stack string
+}
+
+func (m errorMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (retErr error) {
+	// Panics from the methods of the error are captured as zapcore.JSONEncoder
+	// does for zap.Error.
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			retErr = fmt.Errorf("PANIC=%v", rerr)
+		}
+	}()
+
+	// A nil pointer is serialized as "<nil>" without calling its methods, which
+	// are likely to panic.
+	if v := reflect.ValueOf(m.Error); v.Kind() == reflect.Ptr && v.IsNil() {
+		enc.AddString("message", "<nil>")
+		enc.AddString("type", fmt.Sprintf("%T", m.Error))
+
+		return nil
+	}
+
+	msg := m.Error.Error()
+
+	enc.AddString("message", msg)
+	enc.AddString("type", fmt.Sprintf("%T", m.Error))
+
+	stack := m.Stack
+
+	// Errors such as github.com/pkg/errors print their stack trace with %+v.
+	if f, ok := m.Error.(fmt.Formatter); ok {
+		if verbose := fmt.Sprintf("%+v", f); verbose != msg {
+			stack = verbose
+		}
+	}
+
+	if stack != "" {
+		enc.AddString("stack_trace", stack)
+	}
+
+	return nil
+}
+
+type core struct {
+	zapcore.Core
+	opts    Options
+	spanCtx trace.SpanContext
+}
+
+// NewCore wraps a zapcore.Core, usually constructed with a JSON encoder of
+// EncoderConfig, to map each entry into the Elastic Common Schema: the
+// log.origin fields from the entry caller, the error fields from zap.Error,
+// the trace.id and span.id fields from fields constructed by zaptrace.Context
+// or zaptrace.SpanContext and the ecs.version field.
+func (opts Options) NewCore(c zapcore.Core) zapcore.Core {
+	return &core{
+		Core: c.With([]zapcore.Field{zap.String(VersionKey, Version)}),
+		opts: opts,
+	}
+}
+
+// NewCore wraps a zapcore.Core with the default options. See Options.NewCore
+// for details.
+func NewCore(c zapcore.Core) zapcore.Core {
+	return Options{}.NewCore(c)
+}
+
+// mappedFields holds the fields mapped by Options.mapFields.
+type mappedFields struct {
+	// SpanContext is the last valid trace.SpanContext carried by the fields.
+	SpanContext trace.SpanContext
+	// Error is the error of the last zap.Error field.
+	Error error
+	// Fields are the remaining fields with their object and array keys
+	// prefixed by the namespace.
+	Fields []zapcore.Field
+}
+
+func (opts Options) mapFields(fields []zapcore.Field) mappedFields {
+	m := mappedFields{
+		Fields: make([]zapcore.Field, 0, len(fields)),
+	}
+
+	for _, f := range fields {
+		if sc, rest, ok := zaptrace.SplitField(f); ok {
+			if sc.IsValid() {
+				m.SpanContext = sc
+			}
+
+			if rest.Type != zapcore.SkipType {
+				m.Fields = append(m.Fields, rest)
+			}
+
+			continue
+		}
+
+		switch f.Type {
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok && f.Key == ErrorKey {
+				m.Error = err
+
+				continue
+			}
+
+		case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.ReflectType:
+			if opts.Namespace != "" {
+				f.Key = opts.Namespace + "." + f.Key
+			}
+		}
+
+		m.Fields = append(m.Fields, f)
+	}
+
+	return m
+}
+
+func traceFields(spanCtx trace.SpanContext) []zapcore.Field {
+	return []zapcore.Field{
+		zap.String(TraceIDKey, spanCtx.TraceID().String()),
+		zap.String(SpanIDKey, spanCtx.SpanID().String()),
+	}
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	m := c.opts.mapFields(fields)
+
+	spanCtx := m.SpanContext
+	if !spanCtx.IsValid() {
+		spanCtx = c.spanCtx
+	}
+
+	if m.Error != nil {
+		m.Fields = append(m.Fields, zap.Object(ErrorKey, errorMarshaler{Error: m.Error}))
+	}
+
+	return &core{
+		Core:    c.Core.With(m.Fields),
+		opts:    c.opts,
+		spanCtx: spanCtx,
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return wrapcore.Check(c, c.Core, ent, ce)
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(c.Rewrite(ent, fields))
+}
+
+// Rewrite maps the fields of the entry into the Elastic Common Schema fields.
+func (c *core) Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	m := c.opts.mapFields(fields)
+
+	spanCtx := m.SpanContext
+	if !spanCtx.IsValid() {
+		spanCtx = c.spanCtx
+	}
+
+	var special []zapcore.Field
+
+	if ent.Caller.Defined {
+		special = append(special, zap.Object(LogOriginKey, logOriginMarshaler(ent.Caller)))
+	}
+
+	if m.Error != nil {
+		// The entry stack trace is moved into the error object to avoid
+		// writing error.stack_trace twice.
+		special = append(special, zap.Object(ErrorKey, errorMarshaler{
+			Error: m.Error,
+			Stack: ent.Stack,
+		}))
+		ent.Stack = ""
+	}
+
+	if spanCtx.IsValid() {
+		special = append(special, traceFields(spanCtx)...)
+	}
+
+	return ent, append(special, m.Fields...)
+}
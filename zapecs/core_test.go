@@ -0,0 +1,329 @@
+package zapecs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zapecs"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var testSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{1},
+	SpanID:     trace.SpanID{2},
+	TraceFlags: trace.FlagsSampled,
+})
+
+type stackError struct{}
+
+func (stackError) Error() string {
+	return "stack error"
+}
+
+func (e stackError) Format(s fmt.State, verb rune) {
+	io.WriteString(s, e.Error())
+
+	if s.Flag('+') {
+		io.WriteString(s, "\nmain.main\n\tmain.go:1")
+	}
+}
+
+type messageError struct {
+	Message string
+}
+
+func (e *messageError) Error() string {
+	return e.Message
+}
+
+type panicError struct{}
+
+func (panicError) Error() string {
+	panic("boom")
+}
+
+func TestOptions_NewCore(t *testing.T) {
+	type Context struct {
+		Options zapecs.Options
+		Log     func(logger *zap.Logger)
+		Expects []map[string]interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with info entry": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Named("app").Info("hello", zap.Duration("elapsed", time.Second))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "info",
+				"log.logger":      "app",
+				"message":         "hello",
+				"elapsed":         float64(time.Second),
+				zapecs.VersionKey: zapecs.Version,
+			}}
+		},
+
+		"with trace": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zaptrace.SpanContext(testSpanContext)).Info("traced")
+				logger.Info("untraced")
+			}
+
+			tc.Expects = []map[string]interface{}{
+				{
+					"log.level":       "info",
+					"message":         "traced",
+					zapecs.VersionKey: zapecs.Version,
+					zapecs.TraceIDKey: testSpanContext.TraceID().String(),
+					zapecs.SpanIDKey:  testSpanContext.SpanID().String(),
+				},
+				{
+					"log.level":       "info",
+					"message":         "untraced",
+					zapecs.VersionKey: zapecs.Version,
+				},
+			}
+		},
+
+		"with span": func(t *testing.T, tc *Context) {
+			tp := sdktrace.NewTracerProvider()
+			_, span := tp.Tracer("test").Start(context.Background(), "operation",
+				trace.WithTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+			spanCtx := span.SpanContext()
+
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("traced", zaptrace.Span(span))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "info",
+				"message":         "traced",
+				zapecs.VersionKey: zapecs.Version,
+				zapecs.TraceIDKey: spanCtx.TraceID().String(),
+				zapecs.SpanIDKey:  spanCtx.SpanID().String(),
+				"span": map[string]interface{}{
+					"name":      "operation",
+					"kind":      "internal",
+					"startTime": "2024-01-02T03:04:05.000Z",
+					"status":    map[string]interface{}{"code": "Unset"},
+				},
+			}}
+		},
+
+		"with error and entry stack": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Check(zapcore.ErrorLevel, "failed").Write(zap.Error(errors.New("test error")))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "error",
+				"message":         "failed",
+				zapecs.VersionKey: zapecs.Version,
+				zapecs.ErrorKey: map[string]interface{}{
+					"message":     "test error",
+					"type":        "*errors.errorString",
+					"stack_trace": "stack",
+				},
+			}}
+		},
+
+		"with nil pointer error": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("failed", zap.Error((*messageError)(nil)))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "info",
+				"message":         "failed",
+				zapecs.VersionKey: zapecs.Version,
+				zapecs.ErrorKey: map[string]interface{}{
+					"message": "<nil>",
+					"type":    "*zapecs_test.messageError",
+				},
+			}}
+		},
+
+		"with panicking error": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("failed", zap.Error(panicError{}))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "info",
+				"message":         "failed",
+				zapecs.VersionKey: zapecs.Version,
+				zapecs.ErrorKey:   map[string]interface{}{},
+				"errorError":      "PANIC=boom",
+			}}
+		},
+
+		"with error stack": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zap.Error(stackError{})).Warn("failed")
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "warn",
+				"message":         "failed",
+				zapecs.VersionKey: zapecs.Version,
+				zapecs.ErrorKey: map[string]interface{}{
+					"message":     "stack error",
+					"type":        "zapecs_test.stackError",
+					"stack_trace": "stack error\nmain.main\n\tmain.go:1",
+				},
+			}}
+		},
+
+		"with entry stack only": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Error("failed", zap.NamedError("cause", errors.New("test error")))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":         "error",
+				"message":           "failed",
+				"error.stack_trace": "stack",
+				"cause":             "test error",
+				zapecs.VersionKey:   zapecs.Version,
+			}}
+		},
+
+		"with namespace": func(t *testing.T, tc *Context) {
+			tc.Options.Namespace = "app"
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("namespaced",
+					zap.String("key", "value"),
+					zap.Object("message", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+						enc.AddString("text", "hi")
+
+						return nil
+					})),
+					zap.Strings("tags", []string{"a", "b"}),
+				)
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"log.level":       "info",
+				"message":         "namespaced",
+				"key":             "value",
+				"app.message":     map[string]interface{}{"text": "hi"},
+				"app.tags":        []interface{}{"a", "b"},
+				zapecs.VersionKey: zapecs.Version,
+			}}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			var out bytes.Buffer
+
+			enc := zapcore.NewJSONEncoder(zapecs.EncoderConfig())
+			core := tc.Options.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+			tc.Log(zap.New(core, zap.AddStacktrace(zapcore.ErrorLevel), zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+				return stackCore{c}
+			})))
+
+			var entries []map[string]interface{}
+
+			dec := json.NewDecoder(&out)
+			for dec.More() {
+				var entry map[string]interface{}
+				require.NoError(t, dec.Decode(&entry), "entry should be valid json")
+
+				_, err := time.Parse("2006-01-02T15:04:05.000Z0700", entry["@timestamp"].(string))
+				assert.NoError(t, err, "timestamp should be in ISO8601 format")
+				delete(entry, "@timestamp")
+
+				entries = append(entries, entry)
+			}
+
+			assert.Equal(t, tc.Expects, entries, "entries should match")
+		})
+	}
+}
+
+// stackCore replaces the entry stack traces with a stable value.
+type stackCore struct {
+	zapcore.Core
+}
+
+func (c stackCore) With(fields []zapcore.Field) zapcore.Core {
+	return stackCore{c.Core.With(fields)}
+}
+
+func (c stackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c stackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Stack != "" {
+		ent.Stack = "stack"
+	}
+
+	return c.Core.Write(ent, fields)
+}
+
+func TestOptions_NewCore_LogOrigin(t *testing.T) {
+	var out bytes.Buffer
+
+	enc := zapcore.NewJSONEncoder(zapecs.EncoderConfig())
+	core := zapecs.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+	zap.New(core, zap.AddCaller()).Info("hello")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry), "entry should be valid json")
+
+	origin, ok := entry[zapecs.LogOriginKey].(map[string]interface{})
+	require.True(t, ok, "log origin should be encoded")
+	assert.Contains(t, origin["function"], "TestOptions_NewCore_LogOrigin", "log origin function should match")
+
+	file, ok := origin["file"].(map[string]interface{})
+	require.True(t, ok, "log origin file should be encoded")
+	assert.Equal(t, "zapecs/core_test.go", file["name"], "log origin file name should match")
+	assert.NotZero(t, file["line"], "log origin file line should be set")
+}
+
+func TestOptions_NewCore_Sampler(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	sampler := zapcore.NewSamplerWithOptions(obs, time.Minute, 1, 0)
+	logger := zap.New(zapecs.NewCore(sampler))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("hello")
+	}
+
+	assert.Equal(t, 1, logs.Len(), "sampled entries should be dropped")
+}
+
+func TestOptions_NewCore_Tee(t *testing.T) {
+	infoObs, infoLogs := observer.New(zapcore.InfoLevel)
+	errorObs, errorLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(zapecs.NewCore(zapcore.NewTee(infoObs, errorObs)))
+
+	logger.Info("hello")
+	logger.Error("failed", zap.Error(errors.New("boom")))
+
+	assert.Equal(t, 2, infoLogs.Len(), "info core should write both entries")
+	require.Equal(t, 1, errorLogs.Len(), "error core should only write the error entry")
+	assert.Equal(t, map[string]interface{}{"message": "boom", "type": "*errors.errorString"},
+		errorLogs.All()[0].ContextMap()[zapecs.ErrorKey], "error entry should be written with the error object")
+}
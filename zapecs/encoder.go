@@ -0,0 +1,24 @@
+package zapecs
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// EncoderConfig returns a zapcore.EncoderConfig for zapcore.NewJSONEncoder
+// that emits the Elastic Common Schema base and log fields. The caller key is
+// left empty since the log.origin fields are added by the core constructed
+// with Options.NewCore.
+func EncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "log.level",
+		NameKey:        "log.logger",
+		MessageKey:     "message",
+		StacktraceKey:  "error.stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
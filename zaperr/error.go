@@ -0,0 +1,213 @@
+package zaperr
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/adzil/zapf/internal/statuslog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxDepth is the maximum depth of walked causes when
+// Options.MaxDepth is not set.
+const DefaultMaxDepth = 32
+
+// Options configures the field constructed by Options.Error.
+type Options struct {
+	// MaxDepth limits the depth of walked causes. DefaultMaxDepth is used when
+	// it is not set.
+	MaxDepth int
+}
+
+func (opts Options) maxDepth() int {
+	if opts.MaxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+
+	return opts.MaxDepth
+}
+
+// stackTraceOf returns the program counters returned by the StackTrace method
+// of an error. The method must have no parameters and return a slice of
+// uintptr-based values, which is satisfied by github.com/pkg/errors.
+func stackTraceOf(err error) ([]uintptr, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil, false
+	}
+
+	typ := method.Type()
+	if typ.NumIn() != 0 || typ.NumOut() != 1 ||
+		typ.Out(0).Kind() != reflect.Slice || typ.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+
+	return pcs, len(pcs) > 0
+}
+
+// formatStack formats program counters like zap stack traces.
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+	for i := 0; ; i++ {
+		frame, more := frames.Next()
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// unwrap returns the direct causes of an error.
+func unwrap(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	}
+
+	return nil
+}
+
+type causesMarshaler struct {
+	Causes []error
+	Depth  int
+}
+
+func (m causesMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cause := range m.Causes {
+		if err := enc.AppendObject(errorMarshaler{
+			Error: cause,
+			Depth: m.Depth,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type grpcStatus interface {
+	GRPCStatus() *status.Status
+}
+
+type errorMarshaler struct {
+	Error error
+	// Depth is the remaining depth of causes to be walked.
+	Depth int
+}
+
+func (m errorMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (retErr error) {
+	err := m.Error
+
+	// Panics from the methods of the error are captured as zapcore.JSONEncoder
+	// does for zap.Error.
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			retErr = fmt.Errorf("PANIC=%v", rerr)
+		}
+	}()
+
+	// A nil pointer is serialized as "<nil>" without calling its methods, which
+	// are likely to panic.
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Ptr && v.IsNil() {
+		enc.AddString("message", "<nil>")
+		enc.AddString("type", fmt.Sprintf("%T", err))
+
+		return nil
+	}
+
+	enc.AddString("message", err.Error())
+	enc.AddString("type", fmt.Sprintf("%T", err))
+
+	// Only the error itself is checked, as its causes are walked separately.
+	if om, ok := err.(zapcore.ObjectMarshaler); ok {
+		if merr := enc.AddObject("fields", om); merr != nil {
+			return merr
+		}
+	}
+
+	if _, ok := err.(grpcStatus); ok {
+		st, _ := status.FromError(err)
+		if merr := enc.AddObject("status", statuslog.Marshaler{
+			Code:    st.Code().String(),
+			Message: st.Message(),
+			Details: st.Proto().GetDetails(),
+		}); merr != nil {
+			return merr
+		}
+	}
+
+	if pcs, ok := stackTraceOf(err); ok {
+		enc.AddString("stack", formatStack(pcs))
+	}
+
+	causes := unwrap(err)
+	if len(causes) == 0 || m.Depth <= 0 {
+		return nil
+	}
+
+	if len(causes) == 1 {
+		return enc.AddObject("cause", errorMarshaler{
+			Error: causes[0],
+			Depth: m.Depth - 1,
+		})
+	}
+
+	return enc.AddArray("causes", causesMarshaler{
+		Causes: causes,
+		Depth:  m.Depth - 1,
+	})
+}
+
+// Error constructs a field with a given key and an error chain. It serializes
+// the message and concrete type of the error and each of its causes lazily.
+// Causes are walked through both Unwrap() error and Unwrap() []error. Errors
+// implementing zapcore.ObjectMarshaler are serialized with their fields, errors
+// carrying gRPC status with their status details and errors with a
+// StackTrace method returning program counters with their stack trace. Nil
+// pointer errors are serialized with "<nil>" message. The field is skipped if
+// the error is nil.
+func (opts Options) Error(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, errorMarshaler{
+		Error: err,
+		Depth: opts.maxDepth(),
+	})
+}
+
+// Error constructs a field with a given key and an error chain with the
+// default options. See Options.Error for details.
+func Error(key string, err error) zap.Field {
+	return Options{}.Error(key, err)
+}
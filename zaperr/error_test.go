@@ -0,0 +1,223 @@
+package zaperr_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/adzil/zapf/zaperr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fieldsError struct {
+	ID int
+}
+
+func (e fieldsError) Error() string {
+	return fmt.Sprintf("fields error %d", e.ID)
+}
+
+func (e fieldsError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("id", e.ID)
+
+	return nil
+}
+
+// frame mimics the github.com/pkg/errors Frame type.
+type frame uintptr
+
+type stackError struct {
+	stack []frame
+}
+
+func newStackError() *stackError {
+	pcs := make([]uintptr, 1)
+	n := runtime.Callers(1, pcs)
+
+	stack := make([]frame, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = frame(pc)
+	}
+
+	return &stackError{stack: stack}
+}
+
+func (e *stackError) Error() string {
+	return "stack error"
+}
+
+func (e *stackError) StackTrace() []frame {
+	return e.stack
+}
+
+type messageError struct {
+	Message string
+}
+
+func (e *messageError) Error() string {
+	return e.Message
+}
+
+type panicError struct{}
+
+func (panicError) Error() string {
+	panic("panic error")
+}
+
+func TestError(t *testing.T) {
+	type Context struct {
+		Options zaperr.Options
+		Input   error
+		Expects map[string]interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with single error": func(t *testing.T, tc *Context) {
+			tc.Input = errors.New("test error")
+
+			tc.Expects = map[string]interface{}{
+				"message": "test error",
+				"type":    "*errors.errorString",
+			}
+		},
+
+		"with wrapped error": func(t *testing.T, tc *Context) {
+			tc.Input = fmt.Errorf("outer: %w", fieldsError{ID: 1})
+
+			tc.Expects = map[string]interface{}{
+				"message": "outer: fields error 1",
+				"type":    "*fmt.wrapError",
+				"cause": map[string]interface{}{
+					"message": "fields error 1",
+					"type":    "zaperr_test.fieldsError",
+					"fields":  map[string]interface{}{"id": 1},
+				},
+			}
+		},
+
+		"with joined errors": func(t *testing.T, tc *Context) {
+			tc.Input = errors.Join(errors.New("first"), fmt.Errorf("second: %w", errors.New("third")))
+
+			tc.Expects = map[string]interface{}{
+				"message": "first\nsecond: third",
+				"type":    "*errors.joinError",
+				"causes": []interface{}{
+					map[string]interface{}{
+						"message": "first",
+						"type":    "*errors.errorString",
+					},
+					map[string]interface{}{
+						"message": "second: third",
+						"type":    "*fmt.wrapError",
+						"cause": map[string]interface{}{
+							"message": "third",
+							"type":    "*errors.errorString",
+						},
+					},
+				},
+			}
+		},
+
+		"with grpc status": func(t *testing.T, tc *Context) {
+			st, err := status.New(codes.NotFound, "not found").WithDetails(&errdetails.ErrorInfo{
+				Reason: "MISSING",
+			})
+			require.NoError(t, err, "status with details should return nil error")
+
+			tc.Input = fmt.Errorf("lookup: %w", st.Err())
+
+			tc.Expects = map[string]interface{}{
+				"message": "lookup: rpc error: code = NotFound desc = not found",
+				"type":    "*fmt.wrapError",
+				"cause": map[string]interface{}{
+					"message": "rpc error: code = NotFound desc = not found",
+					"type":    "*status.Error",
+					"status": map[string]interface{}{
+						"code":    "NotFound",
+						"message": "not found",
+						"details": []interface{}{
+							map[string]interface{}{
+								"@type":  "type.googleapis.com/google.rpc.ErrorInfo",
+								"reason": "MISSING",
+							},
+						},
+					},
+				},
+			}
+		},
+
+		"with max depth": func(t *testing.T, tc *Context) {
+			tc.Options.MaxDepth = 1
+			tc.Input = fmt.Errorf("first: %w", fmt.Errorf("second: %w", errors.New("third")))
+
+			tc.Expects = map[string]interface{}{
+				"message": "first: second: third",
+				"type":    "*fmt.wrapError",
+				"cause": map[string]interface{}{
+					"message": "second: third",
+					"type":    "*fmt.wrapError",
+				},
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			field := tc.Options.Error("error", tc.Input)
+			assert.Equal(t, "error", field.Key)
+			assert.Equal(t, zapcore.ObjectMarshalerType, field.Type)
+
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+
+			assert.Equal(t, tc.Expects, enc.Fields["error"], "encoded error should match")
+		})
+	}
+}
+
+func TestError_Stack(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	zaperr.Error("error", newStackError()).AddTo(enc)
+
+	m, ok := enc.Fields["error"].(map[string]interface{})
+	require.True(t, ok, "error should be encoded as object")
+	assert.Equal(t, "stack error", m["message"])
+	assert.Contains(t, m["stack"], "zaperr_test.newStackError", "stack should contain the caller function")
+	assert.Contains(t, m["stack"], "error_test.go:", "stack should contain the caller file")
+}
+
+func TestError_Nil(t *testing.T) {
+	assert.Equal(t, zap.Skip(), zaperr.Error("error", nil), "nil error should be skipped")
+}
+
+func TestError_NilPointer(t *testing.T) {
+	var err *messageError
+
+	enc := zapcore.NewMapObjectEncoder()
+	zaperr.Error("error", err).AddTo(enc)
+
+	assert.Equal(t, map[string]interface{}{
+		"message": "<nil>",
+		"type":    "*zaperr_test.messageError",
+	}, enc.Fields["error"], "nil pointer error should be encoded as <nil>")
+}
+
+func TestError_Panic(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	err := enc.AddObject("error", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		zaperr.Error("error", panicError{}).AddTo(enc)
+
+		return nil
+	}))
+
+	require.NoError(t, err, "outer object should be encoded")
+	assert.Contains(t, enc.Fields["error"], "errorError", "panic should be reported as field error")
+}
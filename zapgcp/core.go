@@ -0,0 +1,204 @@
+package zapgcp
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/adzil/zapf/internal/wrapcore"
+	"github.com/adzil/zapf/zaptrace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The keys of the Cloud Logging special fields.
+const (
+	SourceLocationKey = "logging.googleapis.com/sourceLocation"
+	LabelsKey         = "logging.googleapis.com/labels"
+	TraceKey          = "logging.googleapis.com/trace"
+	SpanIDKey         = "logging.googleapis.com/spanId"
+	TraceSampledKey   = "logging.googleapis.com/trace_sampled"
+)
+
+// ErrorEventType is the @type of entries recognized by Error Reporting.
+const ErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// ServiceContext identifies the service of the error entries in Error
+// Reporting.
+type ServiceContext struct {
+	Service string
+	Version string
+}
+
+func (s ServiceContext) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("service", s.Service)
+
+	if s.Version != "" {
+		enc.AddString("version", s.Version)
+	}
+
+	return nil
+}
+
+// Options configures the zapcore.Core constructed by NewCore.
+type Options struct {
+	// ProjectID is the Google Cloud project ID of the traces. The trace
+	// context fields constructed by zaptrace are replaced with the Cloud
+	// Logging trace fields only when it is set.
+	ProjectID string
+	// Labels are added to every entry as Cloud Logging labels.
+	Labels map[string]string
+	// ServiceContext is added to the error entries when its service is set.
+	ServiceContext ServiceContext
+}
+
+type labelsMarshaler map[string]string
+
+func (m labelsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		enc.AddString(k, m[k])
+	}
+
+	return nil
+}
+
+type sourceLocationMarshaler zapcore.EntryCaller
+
+func (m sourceLocationMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("file", m.File)
+	// The line is an int64 which is serialized as string in JSON.
+	enc.AddString("line", strconv.Itoa(m.Line))
+
+	if m.Function != "" {
+		enc.AddString("function", m.Function)
+	}
+
+	return nil
+}
+
+type core struct {
+	zapcore.Core
+	opts    Options
+	spanCtx trace.SpanContext
+}
+
+// NewCore wraps a zapcore.Core, usually constructed with a JSON encoder of
+// EncoderConfig, to add the Cloud Logging special fields to each entry: the
+// source location from the entry caller, the labels, the trace correlation
+// fields from fields constructed by zaptrace.Context or zaptrace.SpanContext,
+// and the Error Reporting @type and service context for entries with
+// zapcore.ErrorLevel and above.
+func (opts Options) NewCore(c zapcore.Core) zapcore.Core {
+	if len(opts.Labels) > 0 {
+		c = c.With([]zapcore.Field{
+			zap.Object(LabelsKey, labelsMarshaler(opts.Labels)),
+		})
+	}
+
+	return &core{
+		Core: c,
+		opts: opts,
+	}
+}
+
+// NewCore wraps a zapcore.Core with the default options. See Options.NewCore
+// for details.
+func NewCore(c zapcore.Core) zapcore.Core {
+	return Options{}.NewCore(c)
+}
+
+// spanContextFromFields returns the last valid trace.SpanContext carried by
+// the fields and the fields without the traceId and spanId. The fields are
+// returned as is when the project ID is not set.
+func (opts Options) spanContextFromFields(fields []zapcore.Field) (trace.SpanContext, []zapcore.Field) {
+	if opts.ProjectID == "" {
+		return trace.SpanContext{}, fields
+	}
+
+	var spanCtx trace.SpanContext
+
+	filtered := make([]zapcore.Field, 0, len(fields))
+
+	for _, f := range fields {
+		sc, rest, ok := zaptrace.SplitField(f)
+		if !ok {
+			filtered = append(filtered, f)
+
+			continue
+		}
+
+		if sc.IsValid() {
+			spanCtx = sc
+		}
+
+		if rest.Type != zapcore.SkipType {
+			filtered = append(filtered, rest)
+		}
+	}
+
+	return spanCtx, filtered
+}
+
+func (opts Options) traceFields(spanCtx trace.SpanContext) []zapcore.Field {
+	return []zapcore.Field{
+		zap.String(TraceKey, "projects/"+opts.ProjectID+"/traces/"+spanCtx.TraceID().String()),
+		zap.String(SpanIDKey, spanCtx.SpanID().String()),
+		zap.Bool(TraceSampledKey, spanCtx.IsSampled()),
+	}
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	spanCtx, filtered := c.opts.spanContextFromFields(fields)
+	if !spanCtx.IsValid() {
+		spanCtx = c.spanCtx
+	}
+
+	return &core{
+		Core:    c.Core.With(filtered),
+		opts:    c.opts,
+		spanCtx: spanCtx,
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return wrapcore.Check(c, c.Core, ent, ce)
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(c.Rewrite(ent, fields))
+}
+
+// Rewrite adds the Cloud Logging special fields of the entry.
+func (c *core) Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	spanCtx, filtered := c.opts.spanContextFromFields(fields)
+	if !spanCtx.IsValid() {
+		spanCtx = c.spanCtx
+	}
+
+	var special []zapcore.Field
+
+	if ent.Level >= zapcore.ErrorLevel {
+		special = append(special, zap.String("@type", ErrorEventType))
+
+		if c.opts.ServiceContext.Service != "" {
+			special = append(special, zap.Object("serviceContext", c.opts.ServiceContext))
+		}
+	}
+
+	if ent.Caller.Defined {
+		special = append(special, zap.Object(SourceLocationKey, sourceLocationMarshaler(ent.Caller)))
+	}
+
+	if spanCtx.IsValid() {
+		special = append(special, c.opts.traceFields(spanCtx)...)
+	}
+
+	return ent, append(special, filtered...)
+}
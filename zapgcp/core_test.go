@@ -0,0 +1,202 @@
+package zapgcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zapgcp"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var testSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{1},
+	SpanID:     trace.SpanID{2},
+	TraceFlags: trace.FlagsSampled,
+})
+
+func TestOptions_NewCore(t *testing.T) {
+	type Context struct {
+		Options zapgcp.Options
+		Log     func(logger *zap.Logger)
+		Expects []map[string]interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with info entry": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Named("app").Info("hello", zap.Duration("elapsed", time.Second))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"severity": "INFO",
+				"logger":   "app",
+				"message":  "hello",
+				"elapsed":  "1s",
+			}}
+		},
+
+		"with labels and trace": func(t *testing.T, tc *Context) {
+			tc.Options.ProjectID = "test-project"
+			tc.Options.Labels = map[string]string{"env": "test"}
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zaptrace.SpanContext(testSpanContext)).Warn("traced")
+				logger.Info("untraced", zap.String("key", "value"))
+			}
+
+			tc.Expects = []map[string]interface{}{
+				{
+					"severity":             "WARNING",
+					"message":              "traced",
+					zapgcp.LabelsKey:       map[string]interface{}{"env": "test"},
+					zapgcp.TraceKey:        "projects/test-project/traces/" + testSpanContext.TraceID().String(),
+					zapgcp.SpanIDKey:       testSpanContext.SpanID().String(),
+					zapgcp.TraceSampledKey: true,
+				},
+				{
+					"severity":       "INFO",
+					"message":        "untraced",
+					zapgcp.LabelsKey: map[string]interface{}{"env": "test"},
+					"key":            "value",
+				},
+			}
+		},
+
+		"with span": func(t *testing.T, tc *Context) {
+			tp := sdktrace.NewTracerProvider()
+			_, span := tp.Tracer("test").Start(context.Background(), "operation",
+				trace.WithTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+			spanCtx := span.SpanContext()
+
+			tc.Options.ProjectID = "test-project"
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("traced", zaptrace.Span(span))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"severity":             "INFO",
+				"message":              "traced",
+				zapgcp.TraceKey:        "projects/test-project/traces/" + spanCtx.TraceID().String(),
+				zapgcp.SpanIDKey:       spanCtx.SpanID().String(),
+				zapgcp.TraceSampledKey: true,
+				"span": map[string]interface{}{
+					"name":      "operation",
+					"kind":      "internal",
+					"startTime": "2024-01-02T03:04:05Z",
+					"status":    map[string]interface{}{"code": "Unset"},
+				},
+			}}
+		},
+
+		"without project id": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("traced", zaptrace.SpanContext(testSpanContext))
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"severity": "INFO",
+				"message":  "traced",
+				"traceId":  testSpanContext.TraceID().String(),
+				"spanId":   testSpanContext.SpanID().String(),
+			}}
+		},
+
+		"with error entry": func(t *testing.T, tc *Context) {
+			tc.Options.ServiceContext = zapgcp.ServiceContext{
+				Service: "app",
+				Version: "v1",
+			}
+			tc.Log = func(logger *zap.Logger) {
+				logger.Error("failed")
+			}
+
+			tc.Expects = []map[string]interface{}{{
+				"severity":       "ERROR",
+				"message":        "failed",
+				"@type":          zapgcp.ErrorEventType,
+				"serviceContext": map[string]interface{}{"service": "app", "version": "v1"},
+			}}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			var out bytes.Buffer
+
+			enc := zapcore.NewJSONEncoder(zapgcp.EncoderConfig())
+			core := tc.Options.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+			tc.Log(zap.New(core))
+
+			var entries []map[string]interface{}
+
+			dec := json.NewDecoder(&out)
+			for dec.More() {
+				var entry map[string]interface{}
+				require.NoError(t, dec.Decode(&entry), "entry should be valid json")
+
+				_, err := time.Parse(time.RFC3339Nano, entry["time"].(string))
+				assert.NoError(t, err, "time should be in RFC3339 format")
+				delete(entry, "time")
+
+				entries = append(entries, entry)
+			}
+
+			assert.Equal(t, tc.Expects, entries, "entries should match")
+		})
+	}
+}
+
+func TestOptions_NewCore_SourceLocation(t *testing.T) {
+	var out bytes.Buffer
+
+	enc := zapcore.NewJSONEncoder(zapgcp.EncoderConfig())
+	core := zapgcp.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.DebugLevel))
+	zap.New(core, zap.AddCaller()).Info("hello")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry), "entry should be valid json")
+
+	assert.NotContains(t, entry, "caller", "caller should not be encoded")
+
+	loc, ok := entry[zapgcp.SourceLocationKey].(map[string]interface{})
+	require.True(t, ok, "source location should be encoded")
+	assert.Contains(t, loc["file"], "core_test.go", "source location file should match")
+	assert.Contains(t, loc["function"], "TestOptions_NewCore_SourceLocation", "source location function should match")
+	assert.NotEmpty(t, loc["line"], "source location line should be set")
+}
+
+func TestOptions_NewCore_Sampler(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	sampler := zapcore.NewSamplerWithOptions(obs, time.Minute, 1, 0)
+	logger := zap.New(zapgcp.NewCore(sampler))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("hello")
+	}
+
+	assert.Equal(t, 1, logs.Len(), "sampled entries should be dropped")
+}
+
+func TestOptions_NewCore_Tee(t *testing.T) {
+	infoObs, infoLogs := observer.New(zapcore.InfoLevel)
+	errorObs, errorLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(zapgcp.NewCore(zapcore.NewTee(infoObs, errorObs)))
+
+	logger.Info("hello")
+	logger.Error("failed")
+
+	assert.Equal(t, 2, infoLogs.Len(), "info core should write both entries")
+	require.Equal(t, 1, errorLogs.Len(), "error core should only write the error entry")
+	assert.Equal(t, zapgcp.ErrorEventType, errorLogs.All()[0].ContextMap()["@type"],
+		"error entry should be written with the special fields")
+}
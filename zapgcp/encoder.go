@@ -0,0 +1,49 @@
+package zapgcp
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Severity converts a zapcore.Level into a Cloud Logging severity name.
+func Severity(lvl zapcore.Level) string {
+	switch {
+	case lvl <= zapcore.DebugLevel:
+		return "DEBUG"
+	case lvl == zapcore.InfoLevel:
+		return "INFO"
+	case lvl == zapcore.WarnLevel:
+		return "WARNING"
+	case lvl == zapcore.ErrorLevel:
+		return "ERROR"
+	case lvl == zapcore.DPanicLevel:
+		return "CRITICAL"
+	case lvl == zapcore.PanicLevel:
+		return "ALERT"
+	}
+
+	return "EMERGENCY"
+}
+
+// LevelEncoder serializes a zapcore.Level into a Cloud Logging severity name.
+func LevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(Severity(lvl))
+}
+
+// EncoderConfig returns a zapcore.EncoderConfig for zapcore.NewJSONEncoder
+// that emits the special fields of Cloud Logging structured logs. The caller
+// key is left empty since the source location is added by the core
+// constructed with Options.NewCore.
+func EncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		MessageKey:     "message",
+		StacktraceKey:  "stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    LevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
@@ -0,0 +1,24 @@
+package zapgcp_test
+
+import (
+	"testing"
+
+	"github.com/adzil/zapf/zapgcp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSeverity(t *testing.T) {
+	for lvl, expects := range map[zapcore.Level]string{
+		zapcore.DebugLevel - 1: "DEBUG",
+		zapcore.DebugLevel:     "DEBUG",
+		zapcore.InfoLevel:      "INFO",
+		zapcore.WarnLevel:      "WARNING",
+		zapcore.ErrorLevel:     "ERROR",
+		zapcore.DPanicLevel:    "CRITICAL",
+		zapcore.PanicLevel:     "ALERT",
+		zapcore.FatalLevel:     "EMERGENCY",
+	} {
+		assert.Equal(t, expects, zapgcp.Severity(lvl), "severity of %s should match", lvl)
+	}
+}
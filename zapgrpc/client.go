@@ -0,0 +1,175 @@
+package zapgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryClientInterceptor constructs a grpc.UnaryClientInterceptor that logs
+// each finished unary call with its method, peer, status code, duration and
+// trace context. The outgoing metadata and the received header and trailer are
+// logged when metadata logging is enabled for the method.
+func (opts Options) UnaryClientInterceptor(logger *zap.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		p := &peer.Peer{}
+		callOpts = append(callOpts, grpc.Peer(p))
+
+		logMetadata := opts.metadata(ctx, method)
+
+		var header, trailer metadata.MD
+		if logMetadata {
+			callOpts = append(callOpts, grpc.Header(&header), grpc.Trailer(&trailer))
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+
+		var fields []zap.Field
+		if logMetadata {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			fields = append(fields,
+				opts.metadataField("metadata", md),
+				opts.metadataField("header", header),
+				opts.metadataField("trailer", trailer),
+			)
+		}
+
+		if opts.payload(ctx, method) {
+			fields = append(fields, messageField("request", req))
+
+			if err == nil {
+				fields = append(fields, messageField("response", reply))
+			}
+		}
+
+		opts.logCall(ctx, logger, "finished client unary call", method, p, start, err, fields...)
+
+		return err
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	ctx        context.Context
+	opts       Options
+	logger     *zap.Logger
+	desc       *grpc.StreamDesc
+	fullMethod string
+	payload    bool
+	metadata   bool
+	start      time.Time
+	summary    streamSummary
+	once       sync.Once
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+
+		var fields []zap.Field
+		if s.payload {
+			fields = append(fields, s.summary.fields(s.opts.StreamSampling)...)
+		}
+
+		if s.metadata {
+			md, _ := metadata.FromOutgoingContext(s.ctx)
+			header, _ := s.ClientStream.Header()
+			fields = append(fields,
+				s.opts.metadataField("metadata", md),
+				s.opts.metadataField("header", header),
+				s.opts.metadataField("trailer", s.ClientStream.Trailer()),
+			)
+		}
+
+		p, _ := peer.FromContext(s.ClientStream.Context())
+		s.opts.logCall(s.ctx, s.logger, "finished client streaming call", s.fullMethod, p, s.start, err, fields...)
+	})
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(err)
+
+		return err
+	}
+
+	if s.payload && s.opts.StreamSampling.sampled(s.summary.received.add(m)) {
+		s.opts.logMessage(s.ctx, s.logger, "received message", s.fullMethod, m)
+	}
+
+	if !s.desc.ServerStreams {
+		s.finish(nil)
+	}
+
+	return nil
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil {
+		// The actual error will be returned by RecvMsg.
+		return err
+	}
+
+	if s.payload && s.opts.StreamSampling.sampled(s.summary.sent.add(m)) {
+		s.opts.logMessage(s.ctx, s.logger, "sent message", s.fullMethod, m)
+	}
+
+	return nil
+}
+
+// StreamClientInterceptor constructs a grpc.StreamClientInterceptor that logs
+// each finished streaming call with its method, peer, status code, duration
+// and trace context. The call is considered finished when receiving a message
+// returns an error or when the only response of a client streaming call is
+// received. Each received and sent message is logged when payload logging is
+// enabled for the method, subject to the stream sampling options, and the
+// outgoing metadata with the received header and trailer when metadata logging
+// is enabled.
+func (opts Options) StreamClientInterceptor(logger *zap.Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			opts.logCall(ctx, logger, "finished client streaming call", method, nil, start, err)
+
+			return nil, err
+		}
+
+		return &clientStream{
+			ClientStream: cs,
+			ctx:          ctx,
+			opts:         opts,
+			logger:       logger,
+			desc:         desc,
+			fullMethod:   method,
+			payload:      opts.payload(ctx, method),
+			metadata:     opts.metadata(ctx, method),
+			start:        start,
+		}, nil
+	}
+}
+
+// UnaryClientInterceptor constructs a grpc.UnaryClientInterceptor with the
+// default options. See Options.UnaryClientInterceptor for details.
+func UnaryClientInterceptor(logger *zap.Logger) grpc.UnaryClientInterceptor {
+	return Options{}.UnaryClientInterceptor(logger)
+}
+
+// StreamClientInterceptor constructs a grpc.StreamClientInterceptor with the
+// default options. See Options.StreamClientInterceptor for details.
+func StreamClientInterceptor(logger *zap.Logger) grpc.StreamClientInterceptor {
+	return Options{}.StreamClientInterceptor(logger)
+}
@@ -0,0 +1,204 @@
+package zapgrpc_test
+
+import (
+	"context"
+	"testing"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/zapgrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	type Context struct {
+		Options      zapgrpc.Options
+		Text         string
+		Metadata     metadata.MD
+		AssertResult func(logs *observer.ObservedLogs, err error)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with successful call and payload": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(context.Context, string) bool {
+				return true
+			}
+			tc.Text = "hello"
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, []zapcore.Level{zapcore.InfoLevel}, levels(logs))
+				assert.Equal(t, "finished client unary call", logs.All()[0].Message)
+				assert.Equal(t, []map[string]interface{}{{
+					"method":   echoMethod,
+					"peer":     "bufconn",
+					"code":     "OK",
+					"traceId":  testSpanContext.TraceID().String(),
+					"spanId":   testSpanContext.SpanID().String(),
+					"request":  map[string]interface{}{"text": "hello"},
+					"response": map[string]interface{}{"text": "hello"},
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with metadata": func(t *testing.T, tc *Context) {
+			tc.Options.Metadata = func(context.Context, string) bool {
+				return true
+			}
+			tc.Options.MetadataOptions.Deny = []string{"x-ignored"}
+			tc.Text = "hello"
+			tc.Metadata = metadata.Pairs(
+				"cookie", "session=secret",
+				"x-request-id", "request-1",
+				"x-ignored", "ignored",
+			)
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				require.Equal(t, 1, logs.Len(), "call should be logged")
+
+				m := logs.All()[0].ContextMap()
+				assert.Equal(t, map[string]interface{}{
+					"cookie":       []interface{}{zapgrpc.RedactedValue},
+					"x-request-id": []interface{}{"request-1"},
+				}, m["metadata"], "outgoing metadata should be logged")
+				assert.Contains(t, m["header"], "content-type", "response header should be logged")
+			}
+		},
+
+		"with failed call": func(t *testing.T, tc *Context) {
+			tc.Text = "fail"
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.Error(t, err, "call should return an error")
+				assert.Equal(t, []zapcore.Level{zapcore.ErrorLevel}, levels(logs))
+				assert.Equal(t, []map[string]interface{}{{
+					"method":  echoMethod,
+					"peer":    "bufconn",
+					"code":    "Internal",
+					"traceId": testSpanContext.TraceID().String(),
+					"spanId":  testSpanContext.SpanID().String(),
+					"error":   map[string]interface{}{"code": "Internal", "message": "failed"},
+				}}, contextMaps(logs))
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+
+			_, conn := newEchoConn(t, nil, []grpc.DialOption{
+				grpc.WithUnaryInterceptor(tc.Options.UnaryClientInterceptor(zap.New(obs))),
+			})
+
+			ctx := trace.ContextWithSpanContext(context.Background(), testSpanContext)
+			ctx = metadata.NewOutgoingContext(ctx, tc.Metadata)
+			err := conn.Invoke(ctx, echoMethod, &marshalerpb.Message{Text: tc.Text}, &marshalerpb.Message{})
+
+			tc.AssertResult(logs, err)
+		})
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	type Context struct {
+		Options      zapgrpc.Options
+		Texts        []string
+		AssertResult func(logs *observer.ObservedLogs, err error)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with successful call": func(t *testing.T, tc *Context) {
+			tc.Texts = []string{"hello", "world"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, "finished client streaming call", logs.All()[0].Message)
+				assert.Equal(t, []map[string]interface{}{{
+					"method": chatMethod,
+					"peer":   "bufconn",
+					"code":   "OK",
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with payload": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(context.Context, string) bool {
+				return true
+			}
+			tc.Texts = []string{"hello"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				require.Equal(t, 3, logs.Len(), "messages and call should be logged")
+				assert.Equal(t, "sent message", logs.All()[0].Message)
+				assert.Equal(t, "received message", logs.All()[1].Message)
+				assert.Equal(t, "finished client streaming call", logs.All()[2].Message)
+			}
+		},
+
+		"with stream sampling": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(context.Context, string) bool {
+				return true
+			}
+			tc.Options.StreamSampling = zapgrpc.StreamSampling{
+				Initial: 2,
+			}
+			tc.Texts = []string{"hello", "hello", "hello"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, 2, logs.FilterMessage("sent message").Len(), "only initial messages should be sent")
+				assert.Equal(t, 2, logs.FilterMessage("received message").Len(), "only initial messages should be received")
+
+				finished := logs.FilterMessage("finished client streaming call")
+				require.Equal(t, 1, finished.Len(), "call should be logged")
+				assert.Equal(t, []map[string]interface{}{{
+					"method":   chatMethod,
+					"peer":     "bufconn",
+					"code":     "OK",
+					"received": map[string]interface{}{"messages": int64(3), "bytes": int64(21)},
+					"sent":     map[string]interface{}{"messages": int64(3), "bytes": int64(21)},
+				}}, contextMaps(finished))
+			}
+		},
+
+		"with failed call": func(t *testing.T, tc *Context) {
+			tc.Texts = []string{"hello", "fail"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.Error(t, err, "call should return an error")
+				assert.Equal(t, []zapcore.Level{zapcore.ErrorLevel}, levels(logs))
+				assert.Equal(t, []map[string]interface{}{{
+					"method": chatMethod,
+					"peer":   "bufconn",
+					"code":   "Internal",
+					"error":  map[string]interface{}{"code": "Internal", "message": "failed"},
+				}}, contextMaps(logs))
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+
+			_, conn := newEchoConn(t, nil, []grpc.DialOption{
+				grpc.WithStreamInterceptor(tc.Options.StreamClientInterceptor(zap.New(obs))),
+			})
+
+			_, err := chat(context.Background(), conn, tc.Texts...)
+
+			tc.AssertResult(logs, err)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package zapgrpc
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// RedactedValue replaces the values of redacted metadata keys.
+	RedactedValue = "[REDACTED]"
+
+	binarySuffix         = "-bin"
+	statusDetailsBinKey  = "grpc-status-details-bin"
+	statusDetailsInvalid = "invalid grpc-status-details-bin value"
+)
+
+// DefaultRedactedKeys lists the credential-bearing metadata keys that are
+// always redacted unless MetadataOptions.DisableDefaultRedact is set.
+var DefaultRedactedKeys = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+	"x-api-key",
+}
+
+// MetadataOptions configures the field constructed by
+// MetadataOptions.Metadata. Keys are matched case-insensitively.
+type MetadataOptions struct {
+	// Allow lists the metadata keys to be logged. All keys are logged when it
+	// is empty.
+	Allow []string
+	// Deny lists the metadata keys that are never logged. It takes precedence
+	// over Allow.
+	Deny []string
+	// Redact lists the metadata keys whose values are replaced with
+	// RedactedValue in addition to DefaultRedactedKeys.
+	Redact []string
+	// DisableDefaultRedact stops redacting DefaultRedactedKeys, so only the
+	// keys in Redact are redacted.
+	DisableDefaultRedact bool
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (opts MetadataOptions) included(key string) bool {
+	if containsKey(opts.Deny, key) {
+		return false
+	}
+
+	return len(opts.Allow) == 0 || containsKey(opts.Allow, key)
+}
+
+func (opts MetadataOptions) redacted(key string) bool {
+	if !opts.DisableDefaultRedact && containsKey(DefaultRedactedKeys, key) {
+		return true
+	}
+
+	return containsKey(opts.Redact, key)
+}
+
+type metadataValuesMarshaler struct {
+	Key      string
+	Values   []string
+	Redacted bool
+}
+
+func (m metadataValuesMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range m.Values {
+		switch {
+		case m.Redacted:
+			enc.AppendString(RedactedValue)
+
+		case m.Key == statusDetailsBinKey:
+			st := &spb.Status{}
+			if err := proto.Unmarshal([]byte(v), st); err != nil {
+				enc.AppendString(statusDetailsInvalid)
+
+				continue
+			}
+
+			if err := enc.AppendObject(statusMarshaler{
+				Status: status.FromProto(st),
+			}); err != nil {
+				return err
+			}
+
+		case strings.HasSuffix(m.Key, binarySuffix):
+			enc.AppendString(base64.StdEncoding.EncodeToString([]byte(v)))
+
+		default:
+			enc.AppendString(v)
+		}
+	}
+
+	return nil
+}
+
+type metadataMarshaler struct {
+	Options  MetadataOptions
+	Metadata metadata.MD
+}
+
+func (m metadataMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m.Metadata))
+	for k := range m.Metadata {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := strings.ToLower(k)
+		if !m.Options.included(key) {
+			continue
+		}
+
+		if err := enc.AddArray(key, metadataValuesMarshaler{
+			Key:      key,
+			Values:   m.Metadata[k],
+			Redacted: m.Options.redacted(key),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Metadata constructs a field with a given key and gRPC metadata. Each
+// metadata key is serialized as an array of its values lazily. Binary values
+// are base64-encoded, except grpc-status-details-bin which is decoded as gRPC
+// status.
+func (opts MetadataOptions) Metadata(key string, md metadata.MD) zap.Field {
+	return zap.Object(key, metadataMarshaler{
+		Options:  opts,
+		Metadata: md,
+	})
+}
+
+// Metadata constructs a field with a given key and gRPC metadata with the
+// default options. See MetadataOptions.Metadata for details.
+func Metadata(key string, md metadata.MD) zap.Field {
+	return MetadataOptions{}.Metadata(key, md)
+}
@@ -0,0 +1,133 @@
+package zapgrpc_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/adzil/zapf/zapgrpc"
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetadata(t *testing.T) {
+	type Context struct {
+		Options zapgrpc.MetadataOptions
+		Input   metadata.MD
+		Expects zaprec.Object
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with default options": func(t *testing.T, tc *Context) {
+			tc.Input = metadata.Pairs(
+				"Authorization", "Bearer secret",
+				"cookie", "session=secret",
+				"x-request-id", "request-1",
+				"x-tags", "a",
+				"x-tags", "b",
+			)
+
+			tc.Expects = zaprec.Object{
+				"authorization": zaprec.Array{zaprec.String(zapgrpc.RedactedValue)},
+				"cookie":        zaprec.Array{zaprec.String(zapgrpc.RedactedValue)},
+				"x-request-id":  zaprec.Array{zaprec.String("request-1")},
+				"x-tags":        zaprec.Array{zaprec.String("a"), zaprec.String("b")},
+			}
+		},
+
+		"with allow and deny lists": func(t *testing.T, tc *Context) {
+			tc.Options.Allow = []string{"X-Request-Id", "x-tags"}
+			tc.Options.Deny = []string{"x-tags"}
+			tc.Input = metadata.Pairs(
+				"x-request-id", "request-1",
+				"x-tags", "a",
+				"x-other", "other",
+			)
+
+			tc.Expects = zaprec.Object{
+				"x-request-id": zaprec.Array{zaprec.String("request-1")},
+			}
+		},
+
+		"with custom redaction": func(t *testing.T, tc *Context) {
+			tc.Options.Redact = []string{"x-secret"}
+			tc.Input = metadata.Pairs(
+				"authorization", "Bearer token",
+				"x-secret", "secret",
+			)
+
+			tc.Expects = zaprec.Object{
+				"authorization": zaprec.Array{zaprec.String(zapgrpc.RedactedValue)},
+				"x-secret":      zaprec.Array{zaprec.String(zapgrpc.RedactedValue)},
+			}
+		},
+
+		"with default redaction disabled": func(t *testing.T, tc *Context) {
+			tc.Options.Redact = []string{"x-secret"}
+			tc.Options.DisableDefaultRedact = true
+			tc.Input = metadata.Pairs(
+				"authorization", "Bearer token",
+				"x-secret", "secret",
+			)
+
+			tc.Expects = zaprec.Object{
+				"authorization": zaprec.Array{zaprec.String("Bearer token")},
+				"x-secret":      zaprec.Array{zaprec.String(zapgrpc.RedactedValue)},
+			}
+		},
+
+		"with binary values": func(t *testing.T, tc *Context) {
+			tc.Input = metadata.Pairs("x-trace-bin", string([]byte{0, 1, 2}))
+
+			tc.Expects = zaprec.Object{
+				"x-trace-bin": zaprec.Array{zaprec.String(base64.StdEncoding.EncodeToString([]byte{0, 1, 2}))},
+			}
+		},
+
+		"with status details": func(t *testing.T, tc *Context) {
+			b, err := proto.Marshal(&spb.Status{
+				Code:    int32(codes.NotFound),
+				Message: "not found",
+			})
+			require.NoError(t, err, "proto marshal should return nil error")
+
+			tc.Input = metadata.Pairs(
+				"grpc-status-details-bin", string(b),
+				"grpc-status-details-bin", "\xff",
+			)
+
+			tc.Expects = zaprec.Object{
+				"grpc-status-details-bin": zaprec.Array{
+					zaprec.Object{
+						"code":    zaprec.String("NotFound"),
+						"message": zaprec.String("not found"),
+					},
+					zaprec.String("invalid grpc-status-details-bin value"),
+				},
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			field := tc.Options.Metadata("metadata", tc.Input)
+			assert.Equal(t, "metadata", field.Key)
+			assert.Equal(t, zapcore.ObjectMarshalerType, field.Type)
+
+			om, ok := field.Interface.(zapcore.ObjectMarshaler)
+			require.True(t, ok, "field should have object marshaler set")
+
+			enc := zaprec.NewObjectEncoder()
+			err := om.MarshalLogObject(enc)
+
+			assert.NoError(t, err, "marshal log object should return nil error")
+			assert.Equal(t, tc.Expects, enc.Result(), "encoded metadata should match")
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package zapgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/adzil/zapf/zapproto"
+	"github.com/adzil/zapf/zaptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Options configures the logging interceptors.
+type Options struct {
+	// Level decides the level of the log entry written when a call finishes.
+	// DefaultLevel is used when it is not set.
+	Level func(ctx context.Context, fullMethod string, code codes.Code) zapcore.Level
+	// Payload decides whether the request and response messages of a call are
+	// logged. Messages are not logged when it is not set.
+	Payload func(ctx context.Context, fullMethod string) bool
+	// StreamSampling configures which streamed messages are logged when payload
+	// logging is enabled. When sampling is enabled, the finished streaming call
+	// is logged with the message count and byte total of each direction.
+	StreamSampling StreamSampling
+	// Metadata decides whether the metadata of a call is logged. Metadata is
+	// not logged when it is not set.
+	Metadata func(ctx context.Context, fullMethod string) bool
+	// MetadataOptions configures the logged metadata. Credential-bearing keys
+	// are redacted by default.
+	MetadataOptions MetadataOptions
+}
+
+// DefaultLevel returns the log level of a finished call based on its status
+// code. Codes caused by the caller are logged at info level, codes caused by
+// the server state at warn level and codes caused by server faults at error
+// level.
+func DefaultLevel(_ context.Context, _ string, code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.Unauthenticated:
+		return zapcore.InfoLevel
+
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return zapcore.WarnLevel
+	}
+
+	return zapcore.ErrorLevel
+}
+
+func (opts Options) level(ctx context.Context, fullMethod string, code codes.Code) zapcore.Level {
+	if opts.Level == nil {
+		return DefaultLevel(ctx, fullMethod, code)
+	}
+
+	return opts.Level(ctx, fullMethod, code)
+}
+
+func (opts Options) payload(ctx context.Context, fullMethod string) bool {
+	if opts.Payload == nil {
+		return false
+	}
+
+	return opts.Payload(ctx, fullMethod)
+}
+
+func (opts Options) metadata(ctx context.Context, fullMethod string) bool {
+	if opts.Metadata == nil {
+		return false
+	}
+
+	return opts.Metadata(ctx, fullMethod)
+}
+
+// metadataField constructs a metadata field if md is not empty.
+func (opts Options) metadataField(key string, md metadata.MD) zap.Field {
+	if len(md) == 0 {
+		return zap.Skip()
+	}
+
+	return opts.MetadataOptions.Metadata(key, md)
+}
+
+// messageField constructs a Protobuf message field if v is a proto.Message.
+func messageField(key string, v interface{}) zap.Field {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return zap.Skip()
+	}
+
+	return zapproto.Message(key, msg)
+}
+
+func peerField(p *peer.Peer) zap.Field {
+	if p == nil || p.Addr == nil {
+		return zap.Skip()
+	}
+
+	return zap.String("peer", p.Addr.String())
+}
+
+// logCall writes the log entry of a finished call.
+func (opts Options) logCall(ctx context.Context, logger *zap.Logger, msg, fullMethod string, p *peer.Peer, start time.Time, err error, fields ...zap.Field) {
+	code := status.Code(err)
+
+	ce := logger.Check(opts.level(ctx, fullMethod, code), msg)
+	if ce == nil {
+		return
+	}
+
+	fields = append([]zap.Field{
+		zap.String("method", fullMethod),
+		peerField(p),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+		zaptrace.Context(ctx),
+	}, fields...)
+
+	ce.Write(append(fields, Status("error", err))...)
+}
+
+// logMessage writes the log entry of a streamed message.
+func (opts Options) logMessage(ctx context.Context, logger *zap.Logger, msg, fullMethod string, m interface{}) {
+	ce := logger.Check(opts.level(ctx, fullMethod, codes.OK), msg)
+	if ce == nil {
+		return
+	}
+
+	ce.Write(
+		zap.String("method", fullMethod),
+		zaptrace.Context(ctx),
+		messageField("message", m),
+	)
+}
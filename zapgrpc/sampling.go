@@ -0,0 +1,74 @@
+package zapgrpc
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamSampling configures which streamed messages are logged per direction
+// when payload logging is enabled. All messages are logged when it is zero.
+type StreamSampling struct {
+	// Initial is the number of first messages logged.
+	Initial int
+	// Thereafter logs every Thereafter-th message after the initial messages.
+	// No more messages are logged after the initial messages when it is zero.
+	Thereafter int
+}
+
+func (s StreamSampling) enabled() bool {
+	return s.Initial > 0 || s.Thereafter > 0
+}
+
+// sampled reports whether the n-th message of a direction is logged.
+func (s StreamSampling) sampled(n int64) bool {
+	if !s.enabled() || n <= int64(s.Initial) {
+		return true
+	}
+
+	return s.Thereafter > 0 && (n-int64(s.Initial))%int64(s.Thereafter) == 0
+}
+
+// messageCounter counts the messages and their Protobuf encoded size of a
+// stream direction. It is safe for concurrent use.
+type messageCounter struct {
+	messages atomic.Int64
+	bytes    atomic.Int64
+}
+
+// add counts a message and returns its sequence number.
+func (c *messageCounter) add(m interface{}) int64 {
+	if msg, ok := m.(proto.Message); ok {
+		c.bytes.Add(int64(proto.Size(msg)))
+	}
+
+	return c.messages.Add(1)
+}
+
+func (c *messageCounter) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("messages", c.messages.Load())
+	enc.AddInt64("bytes", c.bytes.Load())
+
+	return nil
+}
+
+// streamSummary counts the received and sent messages of a stream.
+type streamSummary struct {
+	received messageCounter
+	sent     messageCounter
+}
+
+// fields returns the summary fields of a finished stream if sampling is
+// enabled.
+func (s *streamSummary) fields(sampling StreamSampling) []zap.Field {
+	if !sampling.enabled() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.Object("received", &s.received),
+		zap.Object("sent", &s.sent),
+	}
+}
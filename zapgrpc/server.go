@@ -0,0 +1,120 @@
+package zapgrpc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryServerInterceptor constructs a grpc.UnaryServerInterceptor that logs
+// each finished unary call with its method, peer, status code, duration and
+// trace context. The incoming metadata is logged when metadata logging is
+// enabled for the method.
+func (opts Options) UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		p, _ := peer.FromContext(ctx)
+
+		var fields []zap.Field
+		if opts.metadata(ctx, info.FullMethod) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			fields = append(fields, opts.metadataField("metadata", md))
+		}
+
+		if opts.payload(ctx, info.FullMethod) {
+			fields = append(fields, messageField("request", req))
+
+			if err == nil {
+				fields = append(fields, messageField("response", resp))
+			}
+		}
+
+		opts.logCall(ctx, logger, "finished unary call", info.FullMethod, p, start, err, fields...)
+
+		return resp, err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	opts       Options
+	logger     *zap.Logger
+	fullMethod string
+	summary    streamSummary
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.opts.StreamSampling.sampled(s.summary.received.add(m)) {
+		s.opts.logMessage(s.Context(), s.logger, "received message", s.fullMethod, m)
+	}
+
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil && s.opts.StreamSampling.sampled(s.summary.sent.add(m)) {
+		s.opts.logMessage(s.Context(), s.logger, "sent message", s.fullMethod, m)
+	}
+
+	return err
+}
+
+// StreamServerInterceptor constructs a grpc.StreamServerInterceptor that logs
+// each finished streaming call with its method, peer, status code, duration
+// and trace context. Each received and sent message is logged when payload
+// logging is enabled for the method, subject to the stream sampling options,
+// and the incoming metadata when metadata logging is enabled.
+func (opts Options) StreamServerInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		start := time.Now()
+
+		var wrapped *serverStream
+		if opts.payload(ctx, info.FullMethod) {
+			wrapped = &serverStream{
+				ServerStream: ss,
+				opts:         opts,
+				logger:       logger,
+				fullMethod:   info.FullMethod,
+			}
+			ss = wrapped
+		}
+
+		err := handler(srv, ss)
+
+		var fields []zap.Field
+		if wrapped != nil {
+			fields = append(fields, wrapped.summary.fields(opts.StreamSampling)...)
+		}
+
+		if opts.metadata(ctx, info.FullMethod) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			fields = append(fields, opts.metadataField("metadata", md))
+		}
+
+		p, _ := peer.FromContext(ctx)
+		opts.logCall(ctx, logger, "finished streaming call", info.FullMethod, p, start, err, fields...)
+
+		return err
+	}
+}
+
+// UnaryServerInterceptor constructs a grpc.UnaryServerInterceptor with the
+// default options. See Options.UnaryServerInterceptor for details.
+func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return Options{}.UnaryServerInterceptor(logger)
+}
+
+// StreamServerInterceptor constructs a grpc.StreamServerInterceptor with the
+// default options. See Options.StreamServerInterceptor for details.
+func StreamServerInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return Options{}.StreamServerInterceptor(logger)
+}
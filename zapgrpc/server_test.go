@@ -0,0 +1,290 @@
+package zapgrpc_test
+
+import (
+	"context"
+	"testing"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/zapgrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+var testSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID: trace.TraceID{1},
+	SpanID:  trace.SpanID{2},
+})
+
+// contextMaps returns the context map of the observed entries without the
+// nondeterministic duration field.
+func contextMaps(logs *observer.ObservedLogs) []map[string]interface{} {
+	var result []map[string]interface{}
+
+	for _, entry := range logs.All() {
+		m := entry.ContextMap()
+		delete(m, "duration")
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+func levels(logs *observer.ObservedLogs) []zapcore.Level {
+	var result []zapcore.Level
+
+	for _, entry := range logs.All() {
+		result = append(result, entry.Level)
+	}
+
+	return result
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	type Context struct {
+		Options      zapgrpc.Options
+		Text         string
+		Metadata     metadata.MD
+		AssertResult func(logs *observer.ObservedLogs, err error)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with successful call": func(t *testing.T, tc *Context) {
+			tc.Text = "hello"
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, []zapcore.Level{zapcore.InfoLevel}, levels(logs))
+				assert.Equal(t, "finished unary call", logs.All()[0].Message)
+				assert.Equal(t, []map[string]interface{}{{
+					"method":  echoMethod,
+					"peer":    "bufconn",
+					"code":    "OK",
+					"traceId": testSpanContext.TraceID().String(),
+					"spanId":  testSpanContext.SpanID().String(),
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with payload": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(_ context.Context, fullMethod string) bool {
+				return fullMethod == echoMethod
+			}
+			tc.Text = "hello"
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, []map[string]interface{}{{
+					"method":   echoMethod,
+					"peer":     "bufconn",
+					"code":     "OK",
+					"traceId":  testSpanContext.TraceID().String(),
+					"spanId":   testSpanContext.SpanID().String(),
+					"request":  map[string]interface{}{"text": "hello"},
+					"response": map[string]interface{}{"text": "hello"},
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with metadata": func(t *testing.T, tc *Context) {
+			tc.Options.Metadata = func(context.Context, string) bool {
+				return true
+			}
+			tc.Options.MetadataOptions.Allow = []string{"authorization", "x-request-id"}
+			tc.Text = "hello"
+			tc.Metadata = metadata.Pairs(
+				"authorization", "Bearer secret",
+				"x-request-id", "request-1",
+				"x-ignored", "ignored",
+			)
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, []map[string]interface{}{{
+					"method":  echoMethod,
+					"peer":    "bufconn",
+					"code":    "OK",
+					"traceId": testSpanContext.TraceID().String(),
+					"spanId":  testSpanContext.SpanID().String(),
+					"metadata": map[string]interface{}{
+						"authorization": []interface{}{zapgrpc.RedactedValue},
+						"x-request-id":  []interface{}{"request-1"},
+					},
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with failed call": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(context.Context, string) bool {
+				return true
+			}
+			tc.Text = "fail"
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.Error(t, err, "call should return an error")
+				assert.Equal(t, []zapcore.Level{zapcore.ErrorLevel}, levels(logs))
+				assert.Equal(t, []map[string]interface{}{{
+					"method":  echoMethod,
+					"peer":    "bufconn",
+					"code":    "Internal",
+					"traceId": testSpanContext.TraceID().String(),
+					"spanId":  testSpanContext.SpanID().String(),
+					"request": map[string]interface{}{"text": "fail"},
+					"error":   map[string]interface{}{"code": "Internal", "message": "failed"},
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with level decision": func(t *testing.T, tc *Context) {
+			tc.Options.Level = func(context.Context, string, codes.Code) zapcore.Level {
+				return zapcore.DebugLevel
+			}
+			tc.Text = "hello"
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, 0, logs.Len(), "disabled level should not be logged")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+
+			_, conn := newEchoConn(t, []grpc.ServerOption{
+				grpc.ChainUnaryInterceptor(
+					func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+						return handler(trace.ContextWithSpanContext(ctx, testSpanContext), req)
+					},
+					tc.Options.UnaryServerInterceptor(zap.New(obs)),
+				),
+			}, nil)
+
+			ctx := metadata.NewOutgoingContext(context.Background(), tc.Metadata)
+			err := conn.Invoke(ctx, echoMethod, &marshalerpb.Message{Text: tc.Text}, &marshalerpb.Message{})
+
+			tc.AssertResult(logs, err)
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	type Context struct {
+		Options      zapgrpc.Options
+		Texts        []string
+		AssertResult func(logs *observer.ObservedLogs, err error)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with successful call": func(t *testing.T, tc *Context) {
+			tc.Texts = []string{"hello", "world"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, []map[string]interface{}{{
+					"method": chatMethod,
+					"peer":   "bufconn",
+					"code":   "OK",
+				}}, contextMaps(logs))
+			}
+		},
+
+		"with payload": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(context.Context, string) bool {
+				return true
+			}
+			tc.Texts = []string{"hello"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				require.Equal(t, 3, logs.Len(), "messages and call should be logged")
+				assert.Equal(t, "received message", logs.All()[0].Message)
+				assert.Equal(t, "sent message", logs.All()[1].Message)
+				assert.Equal(t, "finished streaming call", logs.All()[2].Message)
+				assert.Equal(t, []map[string]interface{}{
+					{
+						"method":  chatMethod,
+						"message": map[string]interface{}{"text": "hello"},
+					},
+					{
+						"method":  chatMethod,
+						"message": map[string]interface{}{"text": "hello"},
+					},
+					{
+						"method": chatMethod,
+						"peer":   "bufconn",
+						"code":   "OK",
+					},
+				}, contextMaps(logs))
+			}
+		},
+
+		"with stream sampling": func(t *testing.T, tc *Context) {
+			tc.Options.Payload = func(context.Context, string) bool {
+				return true
+			}
+			tc.Options.StreamSampling = zapgrpc.StreamSampling{
+				Initial:    1,
+				Thereafter: 2,
+			}
+			tc.Texts = []string{"hello", "hello", "hello", "hello", "hello"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "call should return no error")
+				assert.Equal(t, 3, logs.FilterMessage("received message").Len(), "first and every second messages should be received")
+				assert.Equal(t, 3, logs.FilterMessage("sent message").Len(), "first and every second messages should be sent")
+
+				finished := logs.FilterMessage("finished streaming call")
+				require.Equal(t, 1, finished.Len(), "call should be logged")
+				assert.Equal(t, []map[string]interface{}{{
+					"method":   chatMethod,
+					"peer":     "bufconn",
+					"code":     "OK",
+					"received": map[string]interface{}{"messages": int64(5), "bytes": int64(35)},
+					"sent":     map[string]interface{}{"messages": int64(5), "bytes": int64(35)},
+				}}, contextMaps(finished))
+			}
+		},
+
+		"with failed call": func(t *testing.T, tc *Context) {
+			tc.Texts = []string{"hello", "fail"}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.Error(t, err, "call should return an error")
+				assert.Equal(t, []zapcore.Level{zapcore.ErrorLevel}, levels(logs))
+				assert.Equal(t, []map[string]interface{}{{
+					"method": chatMethod,
+					"peer":   "bufconn",
+					"code":   "Internal",
+					"error":  map[string]interface{}{"code": "Internal", "message": "failed"},
+				}}, contextMaps(logs))
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+
+			srv, conn := newEchoConn(t, []grpc.ServerOption{
+				grpc.StreamInterceptor(tc.Options.StreamServerInterceptor(zap.New(obs))),
+			}, nil)
+
+			_, err := chat(context.Background(), conn, tc.Texts...)
+
+			// Wait for the server interceptor to finish logging.
+			srv.GracefulStop()
+			tc.AssertResult(logs, err)
+		})
+	}
+}
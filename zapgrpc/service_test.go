@@ -0,0 +1,160 @@
+package zapgrpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	echoMethod = "/zapf.test.EchoService/Echo"
+	chatMethod = "/zapf.test.EchoService/Chat"
+)
+
+// echoService replies each marshalerpb.Message with the same text. The text
+// "fail" is replied with an internal error status.
+type echoService struct{}
+
+func (echoService) Echo(_ context.Context, req *marshalerpb.Message) (*marshalerpb.Message, error) {
+	if req.Text == "fail" {
+		return nil, status.Error(codes.Internal, "failed")
+	}
+
+	return &marshalerpb.Message{Text: req.Text}, nil
+}
+
+func (echoService) Chat(ss grpc.ServerStream) error {
+	for {
+		req := &marshalerpb.Message{}
+
+		err := ss.RecvMsg(req)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if req.Text == "fail" {
+			return status.Error(codes.Internal, "failed")
+		}
+
+		if err := ss.SendMsg(&marshalerpb.Message{Text: req.Text}); err != nil {
+			return err
+		}
+	}
+}
+
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zapf.test.EchoService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Echo",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := &marshalerpb.Message{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(echoService).Echo(ctx, req.(*marshalerpb.Message))
+				}
+
+				if interceptor == nil {
+					return handler(ctx, req)
+				}
+
+				return interceptor(ctx, req, &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: echoMethod,
+				}, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Chat",
+			Handler: func(srv interface{}, ss grpc.ServerStream) error {
+				return srv.(echoService).Chat(ss)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// newEchoConn starts an in-process echo server and returns the server and a
+// client connection to it.
+func newEchoConn(t *testing.T, serverOpts []grpc.ServerOption, dialOpts []grpc.DialOption) (*grpc.Server, *grpc.ClientConn) {
+	lis := bufconn.Listen(1 << 20)
+
+	srv := grpc.NewServer(serverOpts...)
+	srv.RegisterService(&echoServiceDesc, echoService{})
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	dialOpts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, dialOpts...)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn", dialOpts...)
+	require.NoError(t, err, "grpc new client should return no error")
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return srv, conn
+}
+
+// chat sends each text through the chat stream and returns the echoed texts.
+func chat(ctx context.Context, conn *grpc.ClientConn, texts ...string) ([]string, error) {
+	cs, err := conn.NewStream(ctx, &echoServiceDesc.Streams[0], chatMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+
+	for _, text := range texts {
+		if err := cs.SendMsg(&marshalerpb.Message{Text: text}); err != nil {
+			break
+		}
+
+		resp := &marshalerpb.Message{}
+		if err := cs.RecvMsg(resp); err != nil {
+			return result, err
+		}
+
+		result = append(result, resp.Text)
+	}
+
+	if err := cs.CloseSend(); err != nil {
+		return result, err
+	}
+
+	if err := cs.RecvMsg(&marshalerpb.Message{}); !errors.Is(err, io.EOF) {
+		return result, err
+	}
+
+	return result, nil
+}
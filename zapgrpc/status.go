@@ -0,0 +1,37 @@
+package zapgrpc
+
+import (
+	"github.com/adzil/zapf/internal/statuslog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/status"
+)
+
+// statusMarshaler serializes a gRPC status lazily.
+type statusMarshaler struct {
+	Status *status.Status
+}
+
+func (m statusMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return statuslog.Marshaler{
+		Code:    m.Status.Code().String(),
+		Message: m.Status.Message(),
+		Details: m.Status.Proto().GetDetails(),
+	}.MarshalLogObject(enc)
+}
+
+// Status constructs a field with a given key and the gRPC status of an error.
+// It serializes the status code name, message and each detail message with
+// its type URL lazily. Errors without gRPC status are serialized as unknown
+// status with the error message. The field is skipped if the error is nil.
+func Status(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+
+	st, _ := status.FromError(err)
+
+	return zap.Object(key, statusMarshaler{
+		Status: st,
+	})
+}
@@ -0,0 +1,139 @@
+package zapgrpc_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/adzil/zapf/zapgrpc"
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestStatus(t *testing.T) {
+	type Context struct {
+		Input   error
+		Expects zaprec.Object
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with status without details": func(t *testing.T, tc *Context) {
+			tc.Input = status.Error(codes.NotFound, "not found")
+
+			tc.Expects = zaprec.Object{
+				"code":    zaprec.String("NotFound"),
+				"message": zaprec.String("not found"),
+			}
+		},
+
+		"with status details": func(t *testing.T, tc *Context) {
+			st, err := status.New(codes.InvalidArgument, "invalid request").WithDetails(
+				&errdetails.BadRequest{
+					FieldViolations: []*errdetails.BadRequest_FieldViolation{
+						{
+							Field:       "name",
+							Description: "must not be empty",
+						},
+					},
+				},
+				&errdetails.ErrorInfo{
+					Reason: "EMPTY_NAME",
+					Domain: "zapf.test",
+					Metadata: map[string]string{
+						"field": "name",
+					},
+				},
+			)
+			require.NoError(t, err, "status with details should return nil error")
+
+			tc.Input = st.Err()
+
+			tc.Expects = zaprec.Object{
+				"code":    zaprec.String("InvalidArgument"),
+				"message": zaprec.String("invalid request"),
+				"details": zaprec.Array{
+					zaprec.Object{
+						"@type": zaprec.String("type.googleapis.com/google.rpc.BadRequest"),
+						"fieldViolations": zaprec.Array{
+							zaprec.Object{
+								"field":       zaprec.String("name"),
+								"description": zaprec.String("must not be empty"),
+							},
+						},
+					},
+					zaprec.Object{
+						"@type":  zaprec.String("type.googleapis.com/google.rpc.ErrorInfo"),
+						"reason": zaprec.String("EMPTY_NAME"),
+						"domain": zaprec.String("zapf.test"),
+						"metadata": zaprec.Object{
+							"field": zaprec.String("name"),
+						},
+					},
+				},
+			}
+		},
+
+		"with unresolved status details": func(t *testing.T, tc *Context) {
+			tc.Input = status.FromProto(&spb.Status{
+				Code:    int32(codes.Internal),
+				Message: "failed",
+				Details: []*anypb.Any{
+					{
+						TypeUrl: "type.googleapis.com/zapf.test.Unknown",
+						Value:   []byte("value"),
+					},
+				},
+			}).Err()
+
+			tc.Expects = zaprec.Object{
+				"code":    zaprec.String("Internal"),
+				"message": zaprec.String("failed"),
+				"details": zaprec.Array{
+					zaprec.Object{
+						"@type": zaprec.String("type.googleapis.com/zapf.test.Unknown"),
+						"value": zaprec.String(base64.StdEncoding.EncodeToString([]byte("value"))),
+					},
+				},
+			}
+		},
+
+		"with non-status error": func(t *testing.T, tc *Context) {
+			tc.Input = errors.New("test error")
+
+			tc.Expects = zaprec.Object{
+				"code":    zaprec.String("Unknown"),
+				"message": zaprec.String("test error"),
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			field := zapgrpc.Status("status", tc.Input)
+			assert.Equal(t, "status", field.Key)
+			assert.Equal(t, zapcore.ObjectMarshalerType, field.Type)
+
+			om, ok := field.Interface.(zapcore.ObjectMarshaler)
+			require.True(t, ok, "field should have object marshaler set")
+
+			enc := zaprec.NewObjectEncoder()
+			err := om.MarshalLogObject(enc)
+
+			assert.NoError(t, err, "marshal log object should return nil error")
+			assert.Equal(t, tc.Expects, enc.Result(), "encoded status should match")
+		})
+	}
+}
+
+func TestStatus_Nil(t *testing.T) {
+	assert.Equal(t, zap.Skip(), zapgrpc.Status("status", nil), "nil error should be skipped")
+}
@@ -0,0 +1,143 @@
+package zaphttp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/adzil/zapf/zapproto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxBodySize limits the captured body size. Larger bodies are not decoded.
+const maxBodySize = 1 << 20
+
+// envelopeHeaderSize is the size of the flags and message length prefix of
+// Connect and gRPC-Web envelopes.
+const envelopeHeaderSize = 5
+
+// MessageTypes defines the Protobuf message types of a Connect or gRPC-Web
+// route.
+type MessageTypes struct {
+	// Request is the message type of the request body.
+	Request protoreflect.MessageType
+	// Response is the message type of the response body.
+	Response protoreflect.MessageType
+}
+
+// bodyBuffer captures a body up to maxBodySize.
+type bodyBuffer struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *bodyBuffer) capture(p []byte) {
+	if b.truncated {
+		return
+	}
+
+	if b.buf.Len()+len(p) > maxBodySize {
+		b.truncated = true
+		b.buf.Reset()
+
+		return
+	}
+
+	b.buf.Write(p)
+}
+
+// bodyReader captures the body read by a handler.
+type bodyReader struct {
+	io.ReadCloser
+	body *bodyBuffer
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.body.capture(p[:n])
+
+	return n, err
+}
+
+func unmarshalMessage(mt protoreflect.MessageType, data []byte) (proto.Message, bool) {
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, false
+	}
+
+	return msg, true
+}
+
+// unmarshalEnvelopes decodes the uncompressed messages of Connect streaming or
+// gRPC-Web enveloped body.
+func unmarshalEnvelopes(mt protoreflect.MessageType, data []byte) []proto.Message {
+	var msgs []proto.Message
+
+	for len(data) >= envelopeHeaderSize {
+		flags := data[0]
+		size := binary.BigEndian.Uint32(data[1:envelopeHeaderSize])
+		data = data[envelopeHeaderSize:]
+
+		if uint64(size) > uint64(len(data)) {
+			break
+		}
+
+		payload := data[:size]
+		data = data[size:]
+
+		// Non-zero flags mark compressed messages, the Connect end of stream
+		// message or the gRPC-Web trailers.
+		if flags != 0 {
+			continue
+		}
+
+		if msg, ok := unmarshalMessage(mt, payload); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs
+}
+
+// bodyField constructs a Protobuf message field from a captured body based on
+// its content type. Connect unary bodies are serialized as a single message
+// and enveloped bodies as an array of messages. The field is skipped if the
+// body cannot be decoded.
+func bodyField(key string, mt protoreflect.MessageType, header http.Header, body *bodyBuffer) zap.Field {
+	if mt == nil || body == nil || body.truncated || body.buf.Len() == 0 {
+		return zap.Skip()
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return zap.Skip()
+	}
+
+	switch mediaType {
+	case "application/proto":
+		if enc := header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+			return zap.Skip()
+		}
+
+		msg, ok := unmarshalMessage(mt, body.buf.Bytes())
+		if !ok {
+			return zap.Skip()
+		}
+
+		return zapproto.Message(key, msg)
+
+	case "application/connect+proto", "application/grpc-web", "application/grpc-web+proto":
+		msgs := unmarshalEnvelopes(mt, body.buf.Bytes())
+		if len(msgs) == 0 {
+			return zap.Skip()
+		}
+
+		return zapproto.Messages(key, msgs)
+	}
+
+	return zap.Skip()
+}
@@ -0,0 +1,98 @@
+package zaphttp
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// logRequest writes the access log entry of a finished request.
+func (opts Options) logRequest(logger *zap.Logger, r *http.Request, w *responseWriter, start time.Time, fields ...zap.Field) {
+	status := w.status
+	if status == 0 && !w.hijacked {
+		status = http.StatusOK
+	}
+
+	ce := logger.Check(opts.level(r, status), "finished request")
+	if ce == nil {
+		return
+	}
+
+	fields = append([]zap.Field{
+		opts.Request("request", r),
+		zap.Int("status", status),
+		zap.Int64("bytes", w.bytes),
+		zap.Duration("duration", time.Since(start)),
+		opts.Trace(r),
+	}, fields...)
+
+	if w.hijacked {
+		fields = append(fields, zap.Bool("hijacked", true))
+	}
+
+	ce.Write(fields...)
+}
+
+// Middleware constructs an HTTP middleware that logs each finished request
+// with its request details, response status code, body size, duration and
+// trace context. Panics are recovered, logged with their stack trace and
+// replied with internal server error status if the response has not been
+// written. The request and response bodies of the routes mapped in
+// Options.Messages are decoded and logged as Protobuf messages.
+func (opts Options) Middleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w}
+
+			types, decode := opts.Messages[r.URL.Path]
+
+			var reqBody *bodyBuffer
+			if decode {
+				reqBody = &bodyBuffer{}
+				rw.body = &bodyBuffer{}
+
+				if r.Body != nil && r.Body != http.NoBody {
+					r.Body = &bodyReader{
+						ReadCloser: r.Body,
+						body:       reqBody,
+					}
+				}
+			}
+
+			defer func() {
+				rec := recover()
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				var fields []zap.Field
+				if decode {
+					fields = append(fields,
+						bodyField("requestMessage", types.Request, r.Header, reqBody),
+						bodyField("responseMessage", types.Response, rw.Header(), rw.body),
+					)
+				}
+
+				if rec != nil {
+					fields = append(fields, zap.Any("panic", rec), zap.Stack("stack"))
+
+					if rw.status == 0 && !rw.hijacked {
+						rw.WriteHeader(http.StatusInternalServerError)
+					}
+				}
+
+				opts.logRequest(logger, r, rw, start, fields...)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// Middleware constructs an HTTP middleware with the default options. See
+// Options.Middleware for details.
+func Middleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return Options{}.Middleware(logger)
+}
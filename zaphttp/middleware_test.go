@@ -0,0 +1,227 @@
+package zaphttp_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/zaphttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/proto"
+)
+
+const echoPath = "/zapf.test.EchoService/Echo"
+
+var echoMessageTypes = zaphttp.MessageTypes{
+	Request:  (&marshalerpb.Message{}).ProtoReflect().Type(),
+	Response: (&marshalerpb.Message{}).ProtoReflect().Type(),
+}
+
+// contextMap returns the context map of the only observed entry without the
+// nondeterministic duration and stack fields.
+func contextMap(t *testing.T, logs *observer.ObservedLogs) map[string]interface{} {
+	require.Equal(t, 1, logs.Len(), "request should be logged once")
+
+	m := logs.All()[0].ContextMap()
+	delete(m, "duration")
+	delete(m, "stack")
+
+	return m
+}
+
+func marshalMessage(t *testing.T, text string) []byte {
+	b, err := proto.Marshal(&marshalerpb.Message{Text: text})
+	require.NoError(t, err, "proto marshal should return nil error")
+
+	return b
+}
+
+// envelope wraps each payload with the Connect and gRPC-Web envelope prefix.
+func envelope(flags byte, payloads ...[]byte) []byte {
+	var buf bytes.Buffer
+
+	for _, p := range payloads {
+		buf.WriteByte(flags)
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(p)))
+		buf.Write(p)
+	}
+
+	return buf.Bytes()
+}
+
+func TestMiddleware(t *testing.T) {
+	type Context struct {
+		Options      zaphttp.Options
+		Request      *http.Request
+		Handler      http.HandlerFunc
+		AssertResult func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with written response": func(t *testing.T, tc *Context) {
+			tc.Request = httptest.NewRequest(http.MethodGet, "/items?token=secret", nil)
+			tc.Request.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			tc.Handler = func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.WriteString(w, "created")
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, resp.Code)
+				assert.Equal(t, zapcore.InfoLevel, logs.All()[0].Level)
+				assert.Equal(t, "finished request", logs.All()[0].Message)
+				assert.Equal(t, map[string]interface{}{
+					"request": map[string]interface{}{
+						"method":     "GET",
+						"url":        "/items?token=[REDACTED]",
+						"host":       "example.com",
+						"proto":      "HTTP/1.1",
+						"remoteAddr": "192.0.2.1:1234",
+					},
+					"status":  int64(201),
+					"bytes":   int64(7),
+					"traceId": "0af7651916cd43dd8448eb211c80319c",
+					"spanId":  "b7ad6b7169203331",
+				}, contextMap(t, logs))
+			}
+		},
+
+		"with empty response": func(t *testing.T, tc *Context) {
+			tc.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			tc.Handler = func(http.ResponseWriter, *http.Request) {}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				m := contextMap(t, logs)
+				assert.Equal(t, int64(200), m["status"], "default status should be logged")
+				assert.Equal(t, int64(0), m["bytes"], "empty body size should be logged")
+			}
+		},
+
+		"with panic": func(t *testing.T, tc *Context) {
+			tc.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			tc.Handler = func(http.ResponseWriter, *http.Request) {
+				panic("boom")
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				require.Equal(t, 1, logs.Len(), "request should be logged once")
+				assert.Equal(t, zapcore.ErrorLevel, logs.All()[0].Level)
+				assert.Contains(t, logs.All()[0].ContextMap()["stack"], "TestMiddleware", "stack should be logged")
+
+				m := contextMap(t, logs)
+				assert.Equal(t, int64(500), m["status"])
+				assert.Equal(t, "boom", m["panic"])
+			}
+		},
+
+		"with level decision": func(t *testing.T, tc *Context) {
+			tc.Options.Level = func(*http.Request, int) zapcore.Level {
+				return zapcore.DebugLevel
+			}
+			tc.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			tc.Handler = func(http.ResponseWriter, *http.Request) {}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, 0, logs.Len(), "disabled level should not be logged")
+			}
+		},
+
+		"with connect unary messages": func(t *testing.T, tc *Context) {
+			tc.Options.Messages = map[string]zaphttp.MessageTypes{
+				echoPath: echoMessageTypes,
+			}
+			tc.Request = httptest.NewRequest(http.MethodPost, echoPath, bytes.NewReader(marshalMessage(t, "hello")))
+			tc.Request.Header.Set("Content-Type", "application/proto")
+			tc.Handler = func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err, "read body should return nil error")
+
+				w.Header().Set("Content-Type", "application/proto")
+				_, _ = w.Write(b)
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				m := contextMap(t, logs)
+				assert.Equal(t, map[string]interface{}{"text": "hello"}, m["requestMessage"])
+				assert.Equal(t, map[string]interface{}{"text": "hello"}, m["responseMessage"])
+			}
+		},
+
+		"with grpc-web messages": func(t *testing.T, tc *Context) {
+			tc.Options.Messages = map[string]zaphttp.MessageTypes{
+				echoPath: echoMessageTypes,
+			}
+			tc.Request = httptest.NewRequest(http.MethodPost, echoPath, bytes.NewReader(envelope(0, marshalMessage(t, "hello"))))
+			tc.Request.Header.Set("Content-Type", "application/grpc-web+proto")
+			tc.Handler = func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+
+				w.Header().Set("Content-Type", "application/grpc-web+proto")
+				_, _ = w.Write(envelope(0, marshalMessage(t, "hello"), marshalMessage(t, "world")))
+				_, _ = w.Write(envelope(0x80, []byte("grpc-status: 0\r\n")))
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				m := contextMap(t, logs)
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{"text": "hello"},
+				}, m["requestMessage"])
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{"text": "hello"},
+					map[string]interface{}{"text": "world"},
+				}, m["responseMessage"])
+			}
+		},
+
+		"with unmapped route": func(t *testing.T, tc *Context) {
+			tc.Options.Messages = map[string]zaphttp.MessageTypes{
+				echoPath: echoMessageTypes,
+			}
+			tc.Request = httptest.NewRequest(http.MethodPost, "/other", bytes.NewReader(marshalMessage(t, "hello")))
+			tc.Request.Header.Set("Content-Type", "application/proto")
+			tc.Handler = func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(w, r.Body)
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, resp *httptest.ResponseRecorder) {
+				m := contextMap(t, logs)
+				assert.NotContains(t, m, "requestMessage", "unmapped request should not be decoded")
+				assert.NotContains(t, m, "responseMessage", "unmapped response should not be decoded")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+			resp := httptest.NewRecorder()
+
+			tc.Options.Middleware(zap.New(obs))(tc.Handler).ServeHTTP(resp, tc.Request)
+
+			tc.AssertResult(logs, resp)
+		})
+	}
+}
+
+func TestMiddleware_AbortHandler(t *testing.T) {
+	obs, logs := observer.New(zapcore.InfoLevel)
+
+	handler := zaphttp.Middleware(zap.New(obs))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}, "abort handler panic should be propagated")
+	assert.Equal(t, 0, logs.Len(), "aborted request should not be logged")
+}
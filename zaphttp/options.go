@@ -0,0 +1,208 @@
+package zaphttp
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedValue replaces the values of redacted headers and query parameters.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedHeaders lists the credential-bearing headers that are always
+// redacted unless Options.DisableDefaultRedact is set.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// DefaultRedactedQuery lists the credential-bearing query parameters that are
+// always redacted unless Options.DisableDefaultRedact is set.
+var DefaultRedactedQuery = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"password",
+	"secret",
+	"signature",
+	"token",
+}
+
+// Options configures the HTTP fields and middleware. Header and query
+// parameter names are matched case-insensitively.
+type Options struct {
+	// Headers lists the headers to be logged. No headers are logged when it is
+	// empty.
+	Headers []string
+	// RedactHeaders lists the headers whose values are replaced with
+	// RedactedValue in addition to DefaultRedactedHeaders.
+	RedactHeaders []string
+	// RedactQuery lists the URL query parameters whose values are replaced
+	// with RedactedValue in addition to DefaultRedactedQuery.
+	RedactQuery []string
+	// DisableDefaultRedact stops redacting DefaultRedactedHeaders and
+	// DefaultRedactedQuery, so only the names in RedactHeaders and RedactQuery
+	// are redacted.
+	DisableDefaultRedact bool
+	// Propagator extracts the trace context from the request headers when the
+	// request context has no active span. propagation.TraceContext is used when
+	// it is not set.
+	Propagator propagation.TextMapPropagator
+	// Level decides the level of the access log entry written by the
+	// middleware. DefaultLevel is used when it is not set.
+	Level func(r *http.Request, status int) zapcore.Level
+	// Messages maps the URL path of Connect and gRPC-Web routes to their
+	// Protobuf message types. The middleware decodes and logs the request and
+	// response bodies of the mapped routes.
+	Messages map[string]MessageTypes
+}
+
+// DefaultLevel returns the level of an access log entry based on its response
+// status code. Server errors are logged at error level and everything else at
+// info level.
+func DefaultLevel(_ *http.Request, status int) zapcore.Level {
+	if status >= http.StatusInternalServerError {
+		return zapcore.ErrorLevel
+	}
+
+	return zapcore.InfoLevel
+}
+
+func (opts Options) level(r *http.Request, status int) zapcore.Level {
+	if opts.Level == nil {
+		return DefaultLevel(r, status)
+	}
+
+	return opts.Level(r, status)
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (opts Options) redactHeader(name string) bool {
+	if !opts.DisableDefaultRedact && containsName(DefaultRedactedHeaders, name) {
+		return true
+	}
+
+	return containsName(opts.RedactHeaders, name)
+}
+
+func (opts Options) redactQuery(name string) bool {
+	if !opts.DisableDefaultRedact && containsName(DefaultRedactedQuery, name) {
+		return true
+	}
+
+	return containsName(opts.RedactQuery, name)
+}
+
+func (opts Options) propagator() propagation.TextMapPropagator {
+	if opts.Propagator == nil {
+		return propagation.TraceContext{}
+	}
+
+	return opts.Propagator
+}
+
+// redactURL returns the string form of u with its password and redacted query
+// parameter values replaced. The order of query parameters is preserved.
+func (opts Options) redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		rawName, _, _ := strings.Cut(param, "=")
+
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+
+		if opts.redactQuery(name) {
+			params[i] = rawName + "=" + RedactedValue
+		}
+	}
+
+	redacted := *u
+	redacted.RawQuery = strings.Join(params, "&")
+
+	return redacted.Redacted()
+}
+
+type headerValuesMarshaler struct {
+	Values   []string
+	Redacted bool
+}
+
+func (m headerValuesMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range m.Values {
+		if m.Redacted {
+			v = RedactedValue
+		}
+
+		enc.AppendString(v)
+	}
+
+	return nil
+}
+
+type headerMarshaler struct {
+	Options Options
+	Header  http.Header
+}
+
+func (m headerMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	names := make([]string, 0, len(m.Options.Headers))
+	for _, name := range m.Options.Headers {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+
+	sort.Strings(names)
+
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+
+		values := m.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := enc.AddArray(name, headerValuesMarshaler{
+			Values:   values,
+			Redacted: m.Options.redactHeader(name),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addHeader adds the selected headers to the encoder if there is any.
+func (opts Options) addHeader(enc zapcore.ObjectEncoder, h http.Header) error {
+	if len(opts.Headers) == 0 || len(h) == 0 {
+		return nil
+	}
+
+	return enc.AddObject("headers", headerMarshaler{
+		Options: opts,
+		Header:  h,
+	})
+}
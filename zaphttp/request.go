@@ -0,0 +1,85 @@
+package zaphttp
+
+import (
+	"net/http"
+
+	"github.com/adzil/zapf/zaptrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type requestMarshaler struct {
+	Options Options
+	Request *http.Request
+}
+
+func (m requestMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	r := m.Request
+
+	enc.AddString("method", r.Method)
+	if r.URL != nil {
+		enc.AddString("url", m.Options.redactURL(r.URL))
+	}
+
+	if r.Host != "" {
+		enc.AddString("host", r.Host)
+	}
+
+	enc.AddString("proto", r.Proto)
+
+	if r.RemoteAddr != "" {
+		enc.AddString("remoteAddr", r.RemoteAddr)
+	}
+
+	if r.ContentLength > 0 {
+		enc.AddInt64("contentLength", r.ContentLength)
+	}
+
+	return m.Options.addHeader(enc, r.Header)
+}
+
+// Request constructs a field with a given key and an HTTP request. It
+// serializes the method, URL with redacted query parameters, host, protocol,
+// remote address, content length and selected headers lazily. The field is
+// skipped if the request is nil.
+func (opts Options) Request(key string, r *http.Request) zap.Field {
+	if r == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, requestMarshaler{
+		Options: opts,
+		Request: r,
+	})
+}
+
+// Trace constructs an inline field with the trace context of an HTTP request.
+// The trace context is extracted from the request headers with the propagator
+// when the request context has no valid span context, so requests served
+// without an active span still log the incoming trace context.
+func (opts Options) Trace(r *http.Request) zap.Field {
+	if r == nil {
+		return zap.Skip()
+	}
+
+	ctx := r.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = opts.propagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+	}
+
+	return zaptrace.Context(ctx)
+}
+
+// Request constructs a field with a given key and an HTTP request with the
+// default options. See Options.Request for details.
+func Request(key string, r *http.Request) zap.Field {
+	return Options{}.Request(key, r)
+}
+
+// Trace constructs an inline field with the trace context of an HTTP request
+// with the default options. See Options.Trace for details.
+func Trace(r *http.Request) zap.Field {
+	return Options{}.Trace(r)
+}
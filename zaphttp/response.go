@@ -0,0 +1,56 @@
+package zaphttp
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type responseMarshaler struct {
+	Options  Options
+	Response *http.Response
+	Duration time.Duration
+}
+
+func (m responseMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	resp := m.Response
+
+	enc.AddInt("status", resp.StatusCode)
+	enc.AddString("proto", resp.Proto)
+
+	if resp.ContentLength > 0 {
+		enc.AddInt64("contentLength", resp.ContentLength)
+	}
+
+	if m.Duration > 0 {
+		enc.AddDuration("duration", m.Duration)
+	}
+
+	return m.Options.addHeader(enc, resp.Header)
+}
+
+// Response constructs a field with a given key, an HTTP response and the
+// duration it took to receive. It serializes the status code, protocol,
+// content length, duration and selected headers lazily. The duration is
+// omitted when it is not positive. The field is skipped if the response is
+// nil.
+func (opts Options) Response(key string, resp *http.Response, duration time.Duration) zap.Field {
+	if resp == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, responseMarshaler{
+		Options:  opts,
+		Response: resp,
+		Duration: duration,
+	})
+}
+
+// Response constructs a field with a given key, an HTTP response and the
+// duration it took to receive with the default options. See Options.Response
+// for details.
+func Response(key string, resp *http.Response, duration time.Duration) zap.Field {
+	return Options{}.Response(key, resp, duration)
+}
@@ -0,0 +1,80 @@
+package zaphttp_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zaphttp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestResponse(t *testing.T) {
+	type Context struct {
+		Options  zaphttp.Options
+		Input    *http.Response
+		Duration time.Duration
+		Expects  map[string]interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with response": func(t *testing.T, tc *Context) {
+			tc.Input = &http.Response{
+				StatusCode:    http.StatusOK,
+				Proto:         "HTTP/2.0",
+				ContentLength: 42,
+			}
+			tc.Duration = 3 * time.Second
+
+			tc.Expects = map[string]interface{}{
+				"status":        200,
+				"proto":         "HTTP/2.0",
+				"contentLength": int64(42),
+				"duration":      3 * time.Second,
+			}
+		},
+
+		"with selected headers": func(t *testing.T, tc *Context) {
+			tc.Options.Headers = []string{"Content-Type", "Set-Cookie"}
+			tc.Input = &http.Response{
+				StatusCode:    http.StatusNotFound,
+				Proto:         "HTTP/1.1",
+				ContentLength: -1,
+				Header: http.Header{
+					"Content-Type": {"text/plain"},
+					"Set-Cookie":   {"session=secret"},
+					"X-Ignored":    {"ignored"},
+				},
+			}
+
+			tc.Expects = map[string]interface{}{
+				"status": 404,
+				"proto":  "HTTP/1.1",
+				"headers": map[string]interface{}{
+					"Content-Type": []interface{}{"text/plain"},
+					"Set-Cookie":   []interface{}{zaphttp.RedactedValue},
+				},
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			field := tc.Options.Response("response", tc.Input, tc.Duration)
+			assert.Equal(t, "response", field.Key)
+			assert.Equal(t, zapcore.ObjectMarshalerType, field.Type)
+
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+
+			assert.Equal(t, tc.Expects, enc.Fields["response"], "encoded response should match")
+		})
+	}
+}
+
+func TestResponse_Nil(t *testing.T) {
+	assert.Equal(t, zap.Skip(), zaphttp.Response("response", nil, 0), "nil response should be skipped")
+}
@@ -0,0 +1,74 @@
+package zaphttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// responseWriter records the status code and body size written by a handler.
+// It captures the written body when body is not nil.
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	bytes    int64
+	hijacked bool
+	body     *bodyBuffer
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+
+	if w.body != nil {
+		w.body.capture(p[:n])
+	}
+
+	return n, err
+}
+
+// Flush implements http.Flusher. It does nothing if the underlying
+// http.ResponseWriter does not support flushing.
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker. It returns http.ErrNotSupported if the
+// underlying http.ResponseWriter does not support hijacking.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying http.ResponseWriter for
+// http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
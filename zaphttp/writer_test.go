@@ -0,0 +1,110 @@
+package zaphttp_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adzil/zapf/zaphttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware_Flush(t *testing.T) {
+	obs, logs := observer.New(zapcore.InfoLevel)
+	resp := httptest.NewRecorder()
+
+	handler := zaphttp.Middleware(zap.New(obs))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "chunk")
+
+		f, ok := w.(http.Flusher)
+		require.True(t, ok, "response writer should implement http.Flusher")
+		f.Flush()
+	}))
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, resp.Flushed, "response should be flushed")
+	assert.Equal(t, int64(5), contextMap(t, logs)["bytes"])
+}
+
+func TestMiddleware_Hijack(t *testing.T) {
+	type Context struct {
+		Serve        func(handler http.Handler)
+		AssertResult func(logs *observer.ObservedLogs, err error)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with hijacker": func(t *testing.T, tc *Context) {
+			tc.Serve = func(handler http.Handler) {
+				done := make(chan struct{})
+
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					defer close(done)
+					handler.ServeHTTP(w, r)
+				}))
+				defer srv.Close()
+
+				resp, err := http.Get(srv.URL)
+				require.NoError(t, err, "get should return nil error")
+
+				_ = resp.Body.Close()
+
+				// Wait for the middleware to finish logging.
+				<-done
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				require.NoError(t, err, "hijack should return nil error")
+
+				m := contextMap(t, logs)
+				assert.Equal(t, true, m["hijacked"], "hijacked connection should be logged")
+				assert.Equal(t, int64(0), m["status"], "hijacked connection should have no status")
+			}
+		},
+
+		"without hijacker": func(t *testing.T, tc *Context) {
+			tc.Serve = func(handler http.Handler) {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs, err error) {
+				assert.ErrorIs(t, err, http.ErrNotSupported)
+				assert.NotContains(t, contextMap(t, logs), "hijacked", "failed hijack should not be logged")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+
+			var err error
+
+			tc.Serve(zaphttp.Middleware(zap.New(obs))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				var (
+					conn net.Conn
+					rw   *bufio.ReadWriter
+				)
+
+				conn, rw, err = http.NewResponseController(w).Hijack()
+				if err != nil {
+					return
+				}
+
+				defer conn.Close()
+
+				_, _ = rw.WriteString("HTTP/1.1 204 No Content\r\nConnection: close\r\n\r\n")
+				_ = rw.Flush()
+			})))
+
+			tc.AssertResult(logs, err)
+		})
+	}
+}
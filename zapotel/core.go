@@ -0,0 +1,196 @@
+package zapotel
+
+import (
+	"context"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"github.com/adzil/zapf/zaptrace"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// Options configures the zapcore.Core constructed by NewCore.
+type Options struct {
+	// LoggerProvider is used to create the OpenTelemetry logger. The global
+	// LoggerProvider is used when it is not set.
+	LoggerProvider log.LoggerProvider
+	// LoggerOptions is passed to the LoggerProvider when creating the
+	// OpenTelemetry logger.
+	LoggerOptions []log.LoggerOption
+	// LevelEnabler decides which entries are converted into log records.
+	// All levels are enabled when it is not set.
+	LevelEnabler zapcore.LevelEnabler
+}
+
+type core struct {
+	logger  log.Logger
+	enabler zapcore.LevelEnabler
+	spanCtx trace.SpanContext
+	fields  []zapcore.Field
+}
+
+// NewCore constructs a zapcore.Core that converts zap entries into
+// OpenTelemetry log records and emits them through an OpenTelemetry logger
+// with the given instrumentation scope name. Trace and span IDs are taken from
+// fields constructed by zaptrace.Context or zaptrace.SpanContext.
+func (opts Options) NewCore(name string) zapcore.Core {
+	provider := opts.LoggerProvider
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+
+	enabler := opts.LevelEnabler
+	if enabler == nil {
+		enabler = zapcore.DebugLevel
+	}
+
+	return &core{
+		logger:  provider.Logger(name, opts.LoggerOptions...),
+		enabler: enabler,
+	}
+}
+
+// NewCore constructs a zapcore.Core with the default options. See
+// Options.NewCore for details.
+func NewCore(name string) zapcore.Core {
+	return Options{}.NewCore(name)
+}
+
+// Severity converts a zapcore.Level into a log.Severity.
+func Severity(lvl zapcore.Level) log.Severity {
+	switch {
+	case lvl < zapcore.DebugLevel:
+		return log.SeverityTrace
+	case lvl == zapcore.DebugLevel:
+		return log.SeverityDebug
+	case lvl == zapcore.InfoLevel:
+		return log.SeverityInfo
+	case lvl == zapcore.WarnLevel:
+		return log.SeverityWarn
+	case lvl == zapcore.ErrorLevel:
+		return log.SeverityError
+	case lvl == zapcore.DPanicLevel:
+		return log.SeverityFatal1
+	case lvl == zapcore.PanicLevel:
+		return log.SeverityFatal2
+	case lvl == zapcore.FatalLevel:
+		return log.SeverityFatal3
+	}
+
+	return log.SeverityFatal4
+}
+
+// spanContextFromFields returns the last valid trace.SpanContext carried by
+// the fields and the fields without the traceId and spanId.
+func spanContextFromFields(fields []zapcore.Field) (trace.SpanContext, []zapcore.Field) {
+	var spanCtx trace.SpanContext
+
+	filtered := make([]zapcore.Field, 0, len(fields))
+
+	for _, f := range fields {
+		sc, rest, ok := zaptrace.SplitField(f)
+		if !ok {
+			filtered = append(filtered, f)
+
+			continue
+		}
+
+		if sc.IsValid() {
+			spanCtx = sc
+		}
+
+		if rest.Type != zapcore.SkipType {
+			filtered = append(filtered, rest)
+		}
+	}
+
+	return spanCtx, filtered
+}
+
+func (c *core) context(spanCtx trace.SpanContext) context.Context {
+	if !spanCtx.IsValid() {
+		spanCtx = c.spanCtx
+	}
+
+	return trace.ContextWithSpanContext(context.Background(), spanCtx)
+}
+
+func (c *core) Enabled(lvl zapcore.Level) bool {
+	return c.enabler.Enabled(lvl)
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	spanCtx, filtered := spanContextFromFields(fields)
+	if !spanCtx.IsValid() {
+		spanCtx = c.spanCtx
+	}
+
+	return &core{
+		logger:  c.logger,
+		enabler: c.enabler,
+		spanCtx: spanCtx,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], filtered...),
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+
+	var rec log.Record
+	rec.SetSeverity(Severity(ent.Level))
+
+	if !c.logger.Enabled(c.context(trace.SpanContext{}), rec) {
+		return ce
+	}
+
+	return ce.AddCore(ent, c)
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	spanCtx, filtered := spanContextFromFields(fields)
+
+	var rec log.Record
+	rec.SetTimestamp(ent.Time)
+	rec.SetSeverity(Severity(ent.Level))
+	rec.SetSeverityText(ent.Level.String())
+	rec.SetBody(log.StringValue(ent.Message))
+
+	if ent.LoggerName != "" {
+		rec.AddAttributes(log.String("logger", ent.LoggerName))
+	}
+
+	if ent.Caller.Defined {
+		rec.AddAttributes(
+			log.String("code.filepath", ent.Caller.File),
+			log.Int("code.lineno", ent.Caller.Line),
+			log.String("code.function", ent.Caller.Function),
+		)
+	}
+
+	if ent.Stack != "" {
+		rec.AddAttributes(log.String("code.stacktrace", ent.Stack))
+	}
+
+	enc := fieldenc.NewObjectEncoder[log.Value](valueConverter{})
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+
+	for _, f := range filtered {
+		f.AddTo(enc)
+	}
+
+	rec.AddAttributes(keyValues(enc.KeyValues())...)
+
+	c.logger.Emit(c.context(spanCtx), rec)
+
+	return nil
+}
+
+func (c *core) Sync() error {
+	return nil
+}
@@ -0,0 +1,210 @@
+package zapotel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/internal/protolog"
+	"github.com/adzil/zapf/zapotel"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.records
+}
+
+func attributesOf(r sdklog.Record) []log.KeyValue {
+	var kvs []log.KeyValue
+
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		kvs = append(kvs, kv)
+
+		return true
+	})
+
+	return kvs
+}
+
+func TestCore(t *testing.T) {
+	type Context struct {
+		Log           func(logger *zap.Logger)
+		AssertRecords func(records []sdklog.Record)
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with fields": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.Warn("hello",
+					zap.String("string", "world"),
+					zap.Int("int", 1),
+					zap.Bools("bools", []bool{true, false}),
+					zap.Object("message", protolog.MarshalerOf(&marshalerpb.Message{
+						Text: "hello",
+					})),
+					zap.Namespace("namespace"),
+					zap.Float64("float", 1.5),
+				)
+			}
+
+			tc.AssertRecords = func(records []sdklog.Record) {
+				require.Len(t, records, 1, "there should be one record")
+
+				r := records[0]
+				assert.Equal(t, log.SeverityWarn, r.Severity(), "severity should match")
+				assert.Equal(t, "warn", r.SeverityText(), "severity text should match")
+				assert.Equal(t, log.StringValue("hello"), r.Body(), "body should be the message")
+				assert.False(t, r.TraceID().IsValid(), "trace id should be empty")
+				assert.Equal(t, []log.KeyValue{
+					log.String("string", "world"),
+					log.Int64("int", 1),
+					log.Slice("bools", log.BoolValue(true), log.BoolValue(false)),
+					log.Map("message", log.String("text", "hello")),
+					log.Map("namespace", log.Float64("float", 1.5)),
+				}, attributesOf(r), "attributes should match the fields")
+			}
+		},
+
+		"with trace context at call site": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger.With(zap.String("with", "value")).
+					Error("failed", zaptrace.Context(ctx))
+			}
+
+			tc.AssertRecords = func(records []sdklog.Record) {
+				require.Len(t, records, 1, "there should be one record")
+
+				r := records[0]
+				assert.Equal(t, log.SeverityError, r.Severity(), "severity should match")
+				assert.Equal(t, spanCtx.TraceID(), r.TraceID(), "trace id should match")
+				assert.Equal(t, spanCtx.SpanID(), r.SpanID(), "span id should match")
+				assert.Equal(t, spanCtx.TraceFlags(), r.TraceFlags(), "trace flags should match")
+				assert.Equal(t, []log.KeyValue{
+					log.String("with", "value"),
+				}, attributesOf(r), "attributes should not contain trace context")
+			}
+		},
+
+		"with span": func(t *testing.T, tc *Context) {
+			tp := sdktrace.NewTracerProvider()
+			_, span := tp.Tracer("test").Start(context.Background(), "operation",
+				trace.WithTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+			tc.Log = func(logger *zap.Logger) {
+				logger.Info("traced", zaptrace.Span(span))
+			}
+
+			tc.AssertRecords = func(records []sdklog.Record) {
+				require.Len(t, records, 1, "there should be one record")
+
+				r := records[0]
+				assert.Equal(t, span.SpanContext().TraceID(), r.TraceID(), "trace id should match")
+				assert.Equal(t, span.SpanContext().SpanID(), r.SpanID(), "span id should match")
+				assert.Equal(t, []log.KeyValue{
+					log.Map("span",
+						log.String("name", "operation"),
+						log.String("kind", "internal"),
+						log.String("startTime", "2024-01-02T03:04:05Z"),
+						log.Map("status", log.String("code", "Unset")),
+					),
+				}, attributesOf(r), "attributes should contain the span without trace context")
+			}
+		},
+
+		"with trace context from logger with": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *zap.Logger) {
+				logger = logger.With(zaptrace.Context(ctx))
+				logger.Debug("disabled")
+				logger.Info("hello")
+			}
+
+			tc.AssertRecords = func(records []sdklog.Record) {
+				require.Len(t, records, 1, "only enabled entry should be emitted")
+
+				r := records[0]
+				assert.Equal(t, log.SeverityInfo, r.Severity(), "severity should match")
+				assert.Equal(t, spanCtx.TraceID(), r.TraceID(), "trace id should match")
+				assert.Equal(t, spanCtx.SpanID(), r.SpanID(), "span id should match")
+				assert.Len(t, attributesOf(r), 0, "attributes should be empty")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			exp := &memoryExporter{}
+			provider := sdklog.NewLoggerProvider(
+				sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)),
+			)
+
+			core := zapotel.Options{
+				LoggerProvider: provider,
+				LevelEnabler:   zapcore.InfoLevel,
+			}.NewCore("test")
+
+			tc.Log(zap.New(core))
+
+			tc.AssertRecords(exp.Records())
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	for lvl, expected := range map[zapcore.Level]log.Severity{
+		zapcore.DebugLevel - 1: log.SeverityTrace,
+		zapcore.DebugLevel:     log.SeverityDebug,
+		zapcore.InfoLevel:      log.SeverityInfo,
+		zapcore.WarnLevel:      log.SeverityWarn,
+		zapcore.ErrorLevel:     log.SeverityError,
+		zapcore.DPanicLevel:    log.SeverityFatal1,
+		zapcore.PanicLevel:     log.SeverityFatal2,
+		zapcore.FatalLevel:     log.SeverityFatal3,
+	} {
+		assert.Equal(t, expected, zapotel.Severity(lvl), "severity of %s should match", lvl)
+	}
+}
@@ -0,0 +1,77 @@
+package zapotel
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"go.opentelemetry.io/otel/log"
+)
+
+// valueConverter converts the encoded fields into log.Value. Nested objects
+// and namespaces are converted into map values.
+type valueConverter struct{}
+
+func keyValues(kvs []fieldenc.KeyValue[log.Value]) []log.KeyValue {
+	result := make([]log.KeyValue, len(kvs))
+	for i, kv := range kvs {
+		result[i] = log.KeyValue{Key: kv.Key, Value: kv.Value}
+	}
+
+	return result
+}
+
+func (valueConverter) Object(kvs []fieldenc.KeyValue[log.Value]) log.Value {
+	return log.MapValue(keyValues(kvs)...)
+}
+
+func (valueConverter) Array(vs []log.Value) log.Value {
+	return log.SliceValue(vs...)
+}
+
+func (valueConverter) Binary(b []byte) log.Value {
+	return log.BytesValue(b)
+}
+
+func (valueConverter) Bool(b bool) log.Value {
+	return log.BoolValue(b)
+}
+
+func (valueConverter) Duration(d time.Duration) log.Value {
+	return log.StringValue(d.String())
+}
+
+func (valueConverter) Float64(f float64) log.Value {
+	return log.Float64Value(f)
+}
+
+func (valueConverter) Int64(i int64) log.Value {
+	return log.Int64Value(i)
+}
+
+func (valueConverter) String(s string) log.Value {
+	return log.StringValue(s)
+}
+
+func (valueConverter) Time(t time.Time) log.Value {
+	return log.StringValue(t.Format(time.RFC3339Nano))
+}
+
+func (valueConverter) Uint64(u uint64) log.Value {
+	if u > math.MaxInt64 {
+		return log.StringValue(strconv.FormatUint(u, 10))
+	}
+
+	return log.Int64Value(int64(u))
+}
+
+func (valueConverter) Reflected(v interface{}) (log.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return log.Value{}, err
+	}
+
+	return log.StringValue(string(b)), nil
+}
@@ -0,0 +1,128 @@
+package zapproto
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adzil/zapf/internal/protolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// LoadFiles reads a binary-encoded descriptorpb.FileDescriptorSet from a
+// given path, such as the output of protoc --descriptor_set_out, into a
+// registry for DynamicOptions.Files.
+func LoadFiles(path string) (*protoregistry.Files, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal file descriptor set %s: %w", path, err)
+	}
+
+	return protodesc.NewFiles(set)
+}
+
+// DynamicOptions configures the fields of Protobuf messages decoded from raw
+// bytes without generated Go code.
+type DynamicOptions struct {
+	// Typed serializes the messages with their type URL.
+	Typed bool
+	// Files resolves the message type URLs and the types of
+	// google.protobuf.Any messages. The globally registered types are used
+	// when it is nil.
+	Files *protoregistry.Files
+}
+
+func (opts DynamicOptions) resolver() protolog.Resolver {
+	if opts.Files == nil {
+		return protoregistry.GlobalTypes
+	}
+
+	return dynamicpb.NewTypes(opts.Files)
+}
+
+type rawMessageMarshaler struct {
+	Options DynamicOptions
+	Desc    protoreflect.MessageDescriptor
+	TypeURL string
+	Bytes   []byte
+}
+
+func (m rawMessageMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	r := m.Options.resolver()
+
+	desc := m.Desc
+	if desc == nil {
+		mt, err := r.FindMessageByURL(m.TypeURL)
+		if err != nil {
+			enc.AddString(protolog.ErrorKey, fmt.Sprintf("cannot resolve message type %s: %v", m.TypeURL, err))
+
+			return nil
+		}
+
+		desc = mt.Descriptor()
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	if err := (proto.UnmarshalOptions{Resolver: r}).Unmarshal(m.Bytes, msg); err != nil {
+		enc.AddString(protolog.ErrorKey, fmt.Sprintf("cannot unmarshal message %s: %v", desc.FullName(), err))
+
+		return nil
+	}
+
+	return protolog.Options{
+		Typed:    m.Options.Typed,
+		Resolver: r,
+	}.MarshalerOf(msg).MarshalLogObject(enc)
+}
+
+// RawMessage constructs a field with a given key and binary-encoded Protobuf
+// message of a given descriptor. It will decode and serialize the message
+// lazily. Decoding errors are logged in the "_error" key of the object.
+func (opts DynamicOptions) RawMessage(key string, desc protoreflect.MessageDescriptor, b []byte) zap.Field {
+	if desc == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, rawMessageMarshaler{
+		Options: opts,
+		Desc:    desc,
+		Bytes:   b,
+	})
+}
+
+// AnyMessage constructs a field with a given key and binary-encoded Protobuf
+// message of a given type URL, such as the contents of google.protobuf.Any.
+// It will resolve, decode and serialize the message lazily. Resolution and
+// decoding errors are logged in the "_error" key of the object.
+func (opts DynamicOptions) AnyMessage(key, typeURL string, b []byte) zap.Field {
+	return zap.Object(key, rawMessageMarshaler{
+		Options: opts,
+		TypeURL: typeURL,
+		Bytes:   b,
+	})
+}
+
+// RawMessage constructs a field with a given key and binary-encoded Protobuf
+// message with the default options. See DynamicOptions.RawMessage for
+// details.
+func RawMessage(key string, desc protoreflect.MessageDescriptor, b []byte) zap.Field {
+	return DynamicOptions{}.RawMessage(key, desc, b)
+}
+
+// AnyMessage constructs a field with a given key and binary-encoded Protobuf
+// message with the default options. See DynamicOptions.AnyMessage for
+// details.
+func AnyMessage(key, typeURL string, b []byte) zap.Field {
+	return DynamicOptions{}.AnyMessage(key, typeURL, b)
+}
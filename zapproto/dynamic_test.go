@@ -0,0 +1,206 @@
+package zapproto_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adzil/zapf/zapproto"
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const eventTypeURL = "type.googleapis.com/zapf.dynamic.Event"
+
+// writeFileDescriptorSet writes a descriptor set of messages that are not
+// registered globally and returns its path.
+func writeFileDescriptorSet(t *testing.T) string {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(anypb.File_google_protobuf_any_proto),
+			{
+				Name:       proto.String("zapf/dynamic.proto"),
+				Package:    proto.String("zapf.dynamic"),
+				Syntax:     proto.String("proto3"),
+				Dependency: []string{"google/protobuf/any.proto"},
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Event"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							{
+								Name:     proto.String("name"),
+								JsonName: proto.String("name"),
+								Number:   proto.Int32(1),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+							},
+							{
+								Name:     proto.String("payload"),
+								JsonName: proto.String("payload"),
+								Number:   proto.Int32(2),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+								TypeName: proto.String(".google.protobuf.Any"),
+							},
+						},
+					},
+					{
+						Name: proto.String("Detail"),
+						Field: []*descriptorpb.FieldDescriptorProto{{
+							Name:     proto.String("user_id"),
+							JsonName: proto.String("userId"),
+							Number:   proto.Int32(1),
+							Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := proto.Marshal(set)
+	require.NoError(t, err, "file descriptor set should be marshaled")
+
+	path := filepath.Join(t.TempDir(), "descriptor.binpb")
+	require.NoError(t, os.WriteFile(path, b, 0o644), "file descriptor set should be written")
+
+	return path
+}
+
+// marshalEvent encodes an event with a detail payload using the dynamic types
+// of the given registry.
+func marshalEvent(t *testing.T, files *protoregistry.Files) []byte {
+	types := dynamicpb.NewTypes(files)
+
+	detailType, err := types.FindMessageByName("zapf.dynamic.Detail")
+	require.NoError(t, err, "detail type should be resolved")
+
+	detail := detailType.New()
+	detail.Set(detailType.Descriptor().Fields().ByName("user_id"), protoreflect.ValueOfInt64(42))
+
+	payload, err := anypb.New(detail.Interface())
+	require.NoError(t, err, "detail should be marshaled into any")
+
+	eventType, err := types.FindMessageByName("zapf.dynamic.Event")
+	require.NoError(t, err, "event type should be resolved")
+
+	event := eventType.New()
+	fields := eventType.Descriptor().Fields()
+	event.Set(fields.ByName("name"), protoreflect.ValueOfString("login"))
+	event.Set(fields.ByName("payload"), protoreflect.ValueOfMessage(dynamicpb.NewMessage(payload.ProtoReflect().Descriptor())))
+
+	anyMsg := event.Mutable(fields.ByName("payload")).Message()
+	anyFields := anyMsg.Descriptor().Fields()
+	anyMsg.Set(anyFields.ByName("type_url"), protoreflect.ValueOfString(payload.TypeUrl))
+	anyMsg.Set(anyFields.ByName("value"), protoreflect.ValueOfBytes(payload.Value))
+
+	b, err := proto.Marshal(event.Interface())
+	require.NoError(t, err, "event should be marshaled")
+
+	return b
+}
+
+func TestDynamicOptions(t *testing.T) {
+	files, err := zapproto.LoadFiles(writeFileDescriptorSet(t))
+	require.NoError(t, err, "load files should return nil error")
+
+	eventBytes := marshalEvent(t, files)
+
+	desc, err := files.FindDescriptorByName("zapf.dynamic.Event")
+	require.NoError(t, err, "event descriptor should be found")
+
+	eventDesc, ok := desc.(protoreflect.MessageDescriptor)
+	require.True(t, ok, "event descriptor should be a message descriptor")
+
+	type Context struct {
+		Input        zap.Field
+		Expects      zaprec.Value
+		ExpectsError string
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with any message": func(t *testing.T, tc *Context) {
+			opts := zapproto.DynamicOptions{
+				Typed: true,
+				Files: files,
+			}
+			tc.Input = opts.AnyMessage("event", eventTypeURL, eventBytes)
+
+			tc.Expects = zaprec.Object{
+				"@type": zaprec.String(eventTypeURL),
+				"name":  zaprec.String("login"),
+				"payload": zaprec.Object{
+					"@type":  zaprec.String("type.googleapis.com/zapf.dynamic.Detail"),
+					"userId": zaprec.Int64(42),
+				},
+			}
+		},
+
+		"with raw message": func(t *testing.T, tc *Context) {
+			opts := zapproto.DynamicOptions{
+				Files: files,
+			}
+			tc.Input = opts.RawMessage("event", eventDesc, eventBytes)
+
+			tc.Expects = zaprec.Object{
+				"name": zaprec.String("login"),
+				"payload": zaprec.Object{
+					"@type":  zaprec.String("type.googleapis.com/zapf.dynamic.Detail"),
+					"userId": zaprec.Int64(42),
+				},
+			}
+		},
+
+		"with unresolved type url": func(t *testing.T, tc *Context) {
+			tc.Input = zapproto.AnyMessage("event", eventTypeURL, eventBytes)
+
+			tc.ExpectsError = "cannot resolve message type " + eventTypeURL
+		},
+
+		"with invalid bytes": func(t *testing.T, tc *Context) {
+			tc.Input = zapproto.RawMessage("event", eventDesc, []byte{0xff})
+
+			tc.ExpectsError = "cannot unmarshal message zapf.dynamic.Event"
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			if tc.ExpectsError != "" {
+				obj, ok := zaprec.Field(tc.Input).(zaprec.Object)
+				require.True(t, ok, "dynamic message should be encoded as object")
+				assert.Contains(t, obj["_error"], tc.ExpectsError, "dynamic message should contain error")
+
+				return
+			}
+
+			zaprec.AssertField(t, tc.Expects, tc.Input, "dynamic message should match")
+		})
+	}
+}
+
+func TestRawMessage_Nil(t *testing.T) {
+	assert.Equal(t, zap.Skip(), zapproto.RawMessage("event", nil, nil), "nil descriptor should be skipped")
+}
+
+func TestLoadFiles_Error(t *testing.T) {
+	_, err := zapproto.LoadFiles(filepath.Join(t.TempDir(), "missing.binpb"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "missing file should return not exist error")
+
+	path := filepath.Join(t.TempDir(), "invalid.binpb")
+	require.NoError(t, os.WriteFile(path, []byte{0xff}, 0o644), "invalid file should be written")
+
+	_, err = zapproto.LoadFiles(path)
+	assert.ErrorContains(t, err, "cannot unmarshal file descriptor set", "invalid file should return unmarshal error")
+}
@@ -0,0 +1,98 @@
+package zapproto
+
+import (
+	"github.com/adzil/zapf/internal/fieldenc"
+	logentrypb "github.com/adzil/zapf/internal/gen/go/logentry"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LogEntry is the message written by the encoder constructed with NewEncoder.
+// Its schema is zapf.logentry.LogEntry in logentry/logentry.proto.
+type LogEntry = logentrypb.LogEntry
+
+var bufferPool = buffer.NewPool()
+
+type encoder struct {
+	*fieldenc.ObjectEncoder[*structpb.Value]
+}
+
+// NewEncoder constructs a zapcore.Encoder that serializes each entry into a
+// LogEntry message prefixed with its varint-encoded length, as written by
+// protodelim.MarshalTo. The context fields are serialized into a
+// google.protobuf.Struct, so numbers lose precision beyond 2^53 like in JSON,
+// and times and durations are serialized as strings.
+func NewEncoder() zapcore.Encoder {
+	return encoder{
+		ObjectEncoder: newStructEncoder(),
+	}
+}
+
+func (enc encoder) Clone() zapcore.Encoder {
+	return encoder{
+		ObjectEncoder: enc.ObjectEncoder.Clone(),
+	}
+}
+
+func level(lvl zapcore.Level) logentrypb.Level {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return logentrypb.Level_LEVEL_DEBUG
+	case zapcore.InfoLevel:
+		return logentrypb.Level_LEVEL_INFO
+	case zapcore.WarnLevel:
+		return logentrypb.Level_LEVEL_WARN
+	case zapcore.ErrorLevel:
+		return logentrypb.Level_LEVEL_ERROR
+	case zapcore.DPanicLevel:
+		return logentrypb.Level_LEVEL_DPANIC
+	case zapcore.PanicLevel:
+		return logentrypb.Level_LEVEL_PANIC
+	case zapcore.FatalLevel:
+		return logentrypb.Level_LEVEL_FATAL
+	}
+
+	return logentrypb.Level_LEVEL_UNSPECIFIED
+}
+
+func (enc encoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	senc := enc.ObjectEncoder.Clone()
+	for _, f := range fields {
+		f.AddTo(senc)
+	}
+
+	entry := &logentrypb.LogEntry{
+		Level:   level(ent.Level),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+	}
+
+	if !ent.Time.IsZero() {
+		entry.Timestamp = timestamppb.New(ent.Time)
+	}
+
+	if ent.Caller.Defined {
+		entry.Caller = &logentrypb.Caller{
+			File:     ent.Caller.File,
+			Line:     int32(ent.Caller.Line),
+			Function: ent.Caller.Function,
+		}
+	}
+
+	if kvs := senc.KeyValues(); len(kvs) > 0 {
+		entry.Fields = newStruct(kvs)
+	}
+
+	buf := bufferPool.Get()
+	if _, err := protodelim.MarshalTo(buf, entry); err != nil {
+		buf.Free()
+
+		return nil, err
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,170 @@
+package zapproto_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zapproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// readEntries decodes every length-delimited entry in b.
+func readEntries(t *testing.T, b []byte) []*zapproto.LogEntry {
+	var entries []*zapproto.LogEntry
+
+	r := bufio.NewReader(bytes.NewReader(b))
+	for {
+		entry := &zapproto.LogEntry{}
+
+		err := protodelim.UnmarshalFrom(r, entry)
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+
+		require.NoError(t, err, "entry should be decoded")
+
+		entries = append(entries, entry)
+	}
+}
+
+// assertEntry compares the protojson representation of an entry for
+// readable diffs.
+func assertEntry(t *testing.T, expected string, entry *zapproto.LogEntry) {
+	var expectedEntry zapproto.LogEntry
+	require.NoError(t, protojson.Unmarshal([]byte(expected), &expectedEntry), "expected entry should be valid")
+
+	assert.True(t, proto.Equal(&expectedEntry, entry), "entry should match\nexpected: %s\nactual: %s",
+		protojson.Format(&expectedEntry), protojson.Format(entry))
+}
+
+func TestEncoder_EncodeEntry(t *testing.T) {
+	type Context struct {
+		Entry   zapcore.Entry
+		Fields  []zap.Field
+		Expects string
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with entry metadata": func(t *testing.T, tc *Context) {
+			tc.Entry = zapcore.Entry{
+				Level:      zapcore.WarnLevel,
+				Time:       time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+				LoggerName: "test",
+				Message:    "hello",
+				Caller:     zapcore.NewEntryCaller(0, "main.go", 42, true),
+				Stack:      "stacktrace",
+			}
+
+			tc.Expects = `{
+				"timestamp": "2024-01-02T03:04:05.000000006Z",
+				"level": "LEVEL_WARN",
+				"logger": "test",
+				"caller": {"file": "main.go", "line": 42},
+				"message": "hello",
+				"stack": "stacktrace"
+			}`
+		},
+
+		"with fields": func(t *testing.T, tc *Context) {
+			tc.Entry = zapcore.Entry{
+				Level:   zapcore.DebugLevel,
+				Message: "fields",
+			}
+			tc.Fields = []zap.Field{
+				zap.String("string", "value"),
+				zap.Int("int", -1),
+				zap.Uint8("uint8", 2),
+				zap.Float32("float32", 1.5),
+				zap.Bool("bool", true),
+				zap.Binary("binary", []byte("hello")),
+				zap.ByteString("byteString", []byte("hello")),
+				zap.Complex128("complex", 1+2i),
+				zap.Duration("duration", time.Second),
+				zap.Time("time", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+				zap.Strings("strings", []string{"a", "b"}),
+				zap.Any("reflected", map[string]int{"a": 1}),
+				zap.Object("object", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					enc.AddString("key", "value")
+
+					return nil
+				})),
+				zap.Namespace("namespace"),
+				zap.String("nested", "value"),
+			}
+
+			tc.Expects = `{
+				"level": "LEVEL_DEBUG",
+				"message": "fields",
+				"fields": {
+					"string": "value",
+					"int": -1,
+					"uint8": 2,
+					"float32": 1.5,
+					"bool": true,
+					"binary": "aGVsbG8=",
+					"byteString": "hello",
+					"complex": "(1+2i)",
+					"duration": "1s",
+					"time": "2024-01-02T03:04:05Z",
+					"strings": ["a", "b"],
+					"reflected": {"a": 1},
+					"object": {"key": "value"},
+					"namespace": {"nested": "value"}
+				}
+			}`
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			buf, err := zapproto.NewEncoder().EncodeEntry(tc.Entry, tc.Fields)
+			require.NoError(t, err, "encode entry should return nil error")
+
+			entries := readEntries(t, buf.Bytes())
+			require.Len(t, entries, 1, "buffer should contain one entry")
+
+			assertEntry(t, tc.Expects, entries[0])
+		})
+	}
+}
+
+func TestEncoder_Clone(t *testing.T) {
+	var out bytes.Buffer
+
+	core := zapcore.NewCore(zapproto.NewEncoder(), zapcore.AddSync(&out), zapcore.DebugLevel)
+	logger := zap.New(core).With(zap.String("service", "test"), zap.Namespace("request"))
+
+	logger.With(zap.String("id", "1")).Info("first", zap.Int("attempt", 1))
+	logger.Error("second")
+
+	entries := readEntries(t, out.Bytes())
+	require.Len(t, entries, 2, "output should contain two entries")
+
+	for _, entry := range entries {
+		assert.NotNil(t, entry.GetTimestamp(), "entry should have timestamp")
+		entry.Timestamp = nil
+	}
+
+	assertEntry(t, `{
+		"level": "LEVEL_INFO",
+		"message": "first",
+		"fields": {"service": "test", "request": {"id": "1", "attempt": 1}}
+	}`, entries[0])
+
+	assertEntry(t, `{
+		"level": "LEVEL_ERROR",
+		"message": "second",
+		"fields": {"service": "test", "request": {}}
+	}`, entries[1])
+}
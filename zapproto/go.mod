@@ -3,7 +3,7 @@ module github.com/adzil/zapf/zapproto
 go 1.20
 
 require (
-	github.com/adzil/zapf v0.1.1
+	github.com/adzil/zapf v0.2.0
 	github.com/stretchr/testify v1.8.4
 	go.uber.org/zap v1.26.0
 	google.golang.org/protobuf v1.31.0
@@ -12,7 +12,6 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/objx v0.5.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,9 +3,9 @@ package zapproto_test
 import (
 	"testing"
 
-	rec "github.com/adzil/zapf/internal/fieldrecorder"
 	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
 	"github.com/adzil/zapf/zapproto"
+	"github.com/adzil/zapf/zaprec"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -21,7 +21,7 @@ func TestMessage_Marshal(t *testing.T) {
 	type Context struct {
 		Input     zap.Field
 		FieldName string
-		Expects   rec.Object
+		Expects   zaprec.Object
 	}
 
 	for k, v := range map[string]func(t *testing.T, tc *Context){
@@ -30,8 +30,8 @@ func TestMessage_Marshal(t *testing.T) {
 				Text: "hello",
 			})
 
-			tc.Expects = rec.Object{
-				"text": rec.String("hello"),
+			tc.Expects = zaprec.Object{
+				"text": zaprec.String("hello"),
 			}
 		},
 
@@ -40,9 +40,9 @@ func TestMessage_Marshal(t *testing.T) {
 				Text: "hello",
 			})
 
-			tc.Expects = rec.Object{
-				"@type": rec.String("type.googleapis.com/" + marshalerpbMessageFullName),
-				"text":  rec.String("hello"),
+			tc.Expects = zaprec.Object{
+				"@type": zaprec.String("type.googleapis.com/" + marshalerpbMessageFullName),
+				"text":  zaprec.String("hello"),
 			}
 		},
 	} {
@@ -58,7 +58,7 @@ func TestMessage_Marshal(t *testing.T) {
 			om, ok := tc.Input.Interface.(zapcore.ObjectMarshaler)
 			require.True(t, ok, "field should have object marshaler set")
 
-			enc := rec.NewObjectEncoder(t)
+			enc := zaprec.NewObjectEncoder()
 			err := om.MarshalLogObject(enc)
 
 			assert.NoError(t, err, "marshal log object should return nil error")
@@ -71,7 +71,7 @@ func TestMessages_Marshal(t *testing.T) {
 	type Context struct {
 		Input     zap.Field
 		FieldName string
-		Expects   rec.Array
+		Expects   zaprec.Array
 	}
 
 	for k, v := range map[string]func(t *testing.T, tc *Context){
@@ -85,12 +85,12 @@ func TestMessages_Marshal(t *testing.T) {
 				},
 			})
 
-			tc.Expects = rec.Array{
-				rec.Object{
-					"text": rec.String("hello"),
+			tc.Expects = zaprec.Array{
+				zaprec.Object{
+					"text": zaprec.String("hello"),
 				},
-				rec.Object{
-					"text": rec.String("world"),
+				zaprec.Object{
+					"text": zaprec.String("world"),
 				},
 			}
 		},
@@ -105,14 +105,14 @@ func TestMessages_Marshal(t *testing.T) {
 				},
 			})
 
-			tc.Expects = rec.Array{
-				rec.Object{
-					"@type": rec.String("type.googleapis.com/" + marshalerpbMessageFullName),
-					"text":  rec.String("hello"),
+			tc.Expects = zaprec.Array{
+				zaprec.Object{
+					"@type": zaprec.String("type.googleapis.com/" + marshalerpbMessageFullName),
+					"text":  zaprec.String("hello"),
 				},
-				rec.Object{
-					"@type": rec.String("type.googleapis.com/" + marshalerpbMessageFullName),
-					"text":  rec.String("world"),
+				zaprec.Object{
+					"@type": zaprec.String("type.googleapis.com/" + marshalerpbMessageFullName),
+					"text":  zaprec.String("world"),
 				},
 			}
 		},
@@ -129,7 +129,7 @@ func TestMessages_Marshal(t *testing.T) {
 			am, ok := tc.Input.Interface.(zapcore.ArrayMarshaler)
 			require.True(t, ok, "field should have array marshaler set")
 
-			enc := rec.NewArrayEncoder(t)
+			enc := zaprec.NewArrayEncoder()
 			err := am.MarshalLogArray(enc)
 
 			assert.NoError(t, err, "marshal log array should return nil error")
@@ -144,9 +144,13 @@ func TestMessages_Errors(t *testing.T) {
 	am, ok := field.Interface.(zapcore.ArrayMarshaler)
 	require.True(t, ok, "field should have array marshaler set")
 
-	enc := rec.NewArrayEncoder(t)
+	enc := zaprec.NewArrayEncoder()
 	err := am.MarshalLogArray(enc)
 
-	assert.Error(t, err, "marshal log array should return an error")
-	assert.Len(t, enc.Result(), 0, "there should be no encoded result")
+	assert.NoError(t, err, "marshal log array should return nil error")
+	require.Len(t, enc.Result(), 1, "there should be one encoded message")
+
+	obj, ok := enc.Result()[0].(zaprec.Object)
+	require.True(t, ok, "message should be encoded as object")
+	assert.Contains(t, obj["_error"], "invalid empty type URL", "error should be encoded in place of the message")
 }
@@ -0,0 +1,87 @@
+package zapproto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// valueConverter converts the encoded fields into structpb.Value. Nested
+// objects and namespaces are converted into structs.
+type valueConverter struct{}
+
+func newStructEncoder() *fieldenc.ObjectEncoder[*structpb.Value] {
+	return fieldenc.NewObjectEncoder[*structpb.Value](valueConverter{})
+}
+
+// newStruct converts the fields into structpb.Struct. The last field of
+// duplicate keys is kept.
+func newStruct(kvs []fieldenc.KeyValue[*structpb.Value]) *structpb.Struct {
+	fields := make(map[string]*structpb.Value, len(kvs))
+	for _, kv := range kvs {
+		fields[kv.Key] = kv.Value
+	}
+
+	return &structpb.Struct{
+		Fields: fields,
+	}
+}
+
+func (valueConverter) Object(kvs []fieldenc.KeyValue[*structpb.Value]) *structpb.Value {
+	return structpb.NewStructValue(newStruct(kvs))
+}
+
+func (valueConverter) Array(vs []*structpb.Value) *structpb.Value {
+	return structpb.NewListValue(&structpb.ListValue{Values: vs})
+}
+
+func (valueConverter) Binary(b []byte) *structpb.Value {
+	return structpb.NewStringValue(base64.StdEncoding.EncodeToString(b))
+}
+
+func (valueConverter) Bool(b bool) *structpb.Value {
+	return structpb.NewBoolValue(b)
+}
+
+func (valueConverter) Duration(d time.Duration) *structpb.Value {
+	return structpb.NewStringValue(d.String())
+}
+
+func (valueConverter) Float64(f float64) *structpb.Value {
+	return structpb.NewNumberValue(f)
+}
+
+func (valueConverter) Int64(i int64) *structpb.Value {
+	return structpb.NewNumberValue(float64(i))
+}
+
+func (valueConverter) String(s string) *structpb.Value {
+	return structpb.NewStringValue(s)
+}
+
+func (valueConverter) Time(t time.Time) *structpb.Value {
+	return structpb.NewStringValue(t.Format(time.RFC3339Nano))
+}
+
+func (valueConverter) Uint64(u uint64) *structpb.Value {
+	return structpb.NewNumberValue(float64(u))
+}
+
+// Reflected converts v into structpb.Value through its JSON representation,
+// as zapcore.JSONEncoder does.
+func (valueConverter) Reflected(v interface{}) (*structpb.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var jv interface{}
+	if err := json.Unmarshal(b, &jv); err != nil {
+		return nil, err
+	}
+
+	return structpb.NewValue(jv)
+}
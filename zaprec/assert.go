@@ -0,0 +1,81 @@
+package zaprec
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field records a zap field. It returns the value recorded under the field key,
+// or every recorded field as an Object for inline fields. It returns nil if
+// the field is skipped.
+func Field(f zap.Field) Value {
+	enc := NewObjectEncoder()
+	f.AddTo(enc)
+
+	result := enc.Result()
+
+	switch f.Type {
+	case zapcore.InlineMarshalerType:
+		return result
+	case zapcore.SkipType:
+		return nil
+	}
+
+	v, ok := result[f.Key]
+	if !ok {
+		return nil
+	}
+
+	return v
+}
+
+// AssertField asserts that a zap field records the expected value. See Field
+// for the recorded value of a field. A marshaling error fails the assertion
+// with the error field recorded by zap.
+func AssertField(t assert.TestingT, expected Value, f zap.Field, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	enc := NewObjectEncoder()
+	f.AddTo(enc)
+
+	if errValue, ok := enc.Result()[f.Key+"Error"]; ok && f.Type != zapcore.ErrorType {
+		return assert.Fail(t, fmt.Sprintf("field marshaling returned an error: %v", errValue), msgAndArgs...)
+	}
+
+	return assert.Equal(t, expected, Field(f), msgAndArgs...)
+}
+
+// AssertObject asserts that an object marshaler records the expected object
+// without error.
+func AssertObject(t assert.TestingT, expected Object, om zapcore.ObjectMarshaler, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	enc := NewObjectEncoder()
+	if err := om.MarshalLogObject(enc); err != nil {
+		return assert.Fail(t, "marshal log object returned an error: "+err.Error(), msgAndArgs...)
+	}
+
+	return assert.Equal(t, expected, enc.Result(), msgAndArgs...)
+}
+
+// AssertArray asserts that an array marshaler records the expected array
+// without error.
+func AssertArray(t assert.TestingT, expected Array, am zapcore.ArrayMarshaler, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	enc := NewArrayEncoder()
+	if err := am.MarshalLogArray(enc); err != nil {
+		return assert.Fail(t, "marshal log array returned an error: "+err.Error(), msgAndArgs...)
+	}
+
+	return assert.Equal(t, expected, enc.Result(), msgAndArgs...)
+}
@@ -0,0 +1,144 @@
+package zaprec_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingT records the failures of an assertion.
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+var testObject = zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", "zapf")
+
+	return nil
+})
+
+func TestField(t *testing.T) {
+	type Context struct {
+		Input   zap.Field
+		Expects zaprec.Value
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with object field": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Object("object", testObject)
+
+			tc.Expects = zaprec.Object{"name": zaprec.String("zapf")}
+		},
+
+		"with primitive field": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Int("int", 1)
+
+			tc.Expects = zaprec.Int64(1)
+		},
+
+		"with inline field": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Inline(testObject)
+
+			tc.Expects = zaprec.Object{"name": zaprec.String("zapf")}
+		},
+
+		"with skipped field": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Skip()
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			assert.Equal(t, tc.Expects, zaprec.Field(tc.Input), "recorded field should match")
+		})
+	}
+}
+
+func TestAssertField(t *testing.T) {
+	type Context struct {
+		Input        zap.Field
+		Expects      zaprec.Value
+		AssertResult func(ok bool, errs []string)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with matching value": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Object("object", testObject)
+			tc.Expects = zaprec.Object{"name": zaprec.String("zapf")}
+
+			tc.AssertResult = func(ok bool, errs []string) {
+				assert.True(t, ok, "assertion should pass")
+				assert.Empty(t, errs, "assertion should report no error")
+			}
+		},
+
+		"with mismatched value": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Object("object", testObject)
+			tc.Expects = zaprec.Object{"name": zaprec.String("other")}
+
+			tc.AssertResult = func(ok bool, errs []string) {
+				assert.False(t, ok, "assertion should fail")
+				assert.Len(t, errs, 1, "assertion should report an error")
+				assert.Contains(t, errs[0], "Diff:", "assertion should report a diff")
+			}
+		},
+
+		"with marshaling error": func(t *testing.T, tc *Context) {
+			tc.Input = zap.Object("object", zapcore.ObjectMarshalerFunc(func(zapcore.ObjectEncoder) error {
+				return errors.New("test error")
+			}))
+
+			tc.AssertResult = func(ok bool, errs []string) {
+				assert.False(t, ok, "assertion should fail")
+				assert.Len(t, errs, 1, "assertion should report an error")
+				assert.Contains(t, errs[0], "test error", "assertion should report the marshaling error")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			rt := &recordingT{}
+			ok := zaprec.AssertField(rt, tc.Expects, tc.Input)
+
+			tc.AssertResult(ok, rt.errors)
+		})
+	}
+}
+
+func TestAssertObject(t *testing.T) {
+	rt := &recordingT{}
+
+	assert.True(t, zaprec.AssertObject(rt, zaprec.Object{"name": zaprec.String("zapf")}, testObject), "assertion should pass")
+	assert.False(t, zaprec.AssertObject(rt, zaprec.Object{}, zapcore.ObjectMarshalerFunc(func(zapcore.ObjectEncoder) error {
+		return errors.New("test error")
+	})), "assertion should fail on error")
+	assert.Len(t, rt.errors, 1, "only the failed assertion should be reported")
+}
+
+func TestAssertArray(t *testing.T) {
+	rt := &recordingT{}
+
+	assert.True(t, zaprec.AssertArray(rt, zaprec.Array{zaprec.Int(1)}, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt(1)
+
+		return nil
+	})), "assertion should pass")
+	assert.False(t, zaprec.AssertArray(rt, zaprec.Array{zaprec.Int64(1)}, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt(1)
+
+		return nil
+	})), "assertion should fail on mismatched type")
+	assert.Len(t, rt.errors, 1, "only the failed assertion should be reported")
+}
@@ -0,0 +1,197 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// NormalizedValue replaces the normalized values in golden files.
+	NormalizedValue = "<normalized>"
+	// UpdateEnv is the environment variable that updates the golden files
+	// instead of comparing them when it is set to a non-empty value.
+	UpdateEnv = "ZAPREC_UPDATE_GOLDEN"
+)
+
+// Options configures the golden file assertions.
+type Options struct {
+	// Dir is the directory of the golden files. "testdata" is used when it is
+	// empty.
+	Dir string
+	// Normalize lists the object keys whose values are replaced with
+	// NormalizedValue at any depth. Time values are always normalized.
+	Normalize []string
+}
+
+func (opts Options) path(name string) string {
+	dir := opts.Dir
+	if dir == "" {
+		dir = "testdata"
+	}
+
+	return filepath.Join(dir, name+".golden.json")
+}
+
+func (opts Options) normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			if containsKey(opts.Normalize, k) {
+				v[k] = NormalizedValue
+
+				continue
+			}
+
+			v[k] = opts.normalize(elem)
+		}
+
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = opts.normalize(elem)
+		}
+	}
+
+	return v
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canonical rewrites JSON with sorted object keys, two spaces indentation and
+// normalized values.
+func (opts Options) canonical(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(opts.normalize(v)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func normalizedTimeEncoder(_ time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(NormalizedValue)
+}
+
+// EncodeFields encodes fields through a zapcore.JSONEncoder into canonical
+// JSON with sorted object keys. Time values and the values of normalized keys
+// are replaced with NormalizedValue.
+func (opts Options) EncodeFields(fields ...zap.Field) ([]byte, error) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		EncodeTime:     normalizedTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	})
+
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Free()
+
+	return opts.canonical(buf.Bytes())
+}
+
+// Assert compares canonical JSON with the golden file of the given name, or
+// updates the golden file when the UpdateEnv environment variable is set.
+func (opts Options) Assert(t *testing.T, name string, got []byte) bool {
+	t.Helper()
+
+	path := opts.path(name)
+
+	if os.Getenv(UpdateEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "golden file directory should be created")
+		require.NoError(t, os.WriteFile(path, got, 0o644), "golden file should be written")
+
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file should be readable, run the test with %s=1 to create it", UpdateEnv)
+
+	return assert.Equal(t, string(expected), string(got), "output should match golden file %s", path)
+}
+
+// AssertFields encodes fields with EncodeFields and compares the result with
+// the golden file of the given name.
+func (opts Options) AssertFields(t *testing.T, name string, fields ...zap.Field) bool {
+	t.Helper()
+
+	got, err := opts.EncodeFields(fields...)
+	require.NoError(t, err, "fields should be encoded")
+
+	return opts.Assert(t, name, got)
+}
+
+// AssertValue converts a recorded value with zaprec.JSON and compares the
+// result with the golden file of the given name. Time values are converted
+// into strings and not normalized.
+func (opts Options) AssertValue(t *testing.T, name string, v zaprec.Value) bool {
+	t.Helper()
+
+	b, err := zaprec.JSON(v)
+	require.NoError(t, err, "recorded value should be converted into json")
+
+	got, err := opts.canonical(b)
+	require.NoError(t, err, "recorded value should be canonicalized")
+
+	return opts.Assert(t, name, got)
+}
+
+// EncodeFields encodes fields with the default options. See
+// Options.EncodeFields for details.
+func EncodeFields(fields ...zap.Field) ([]byte, error) {
+	return Options{}.EncodeFields(fields...)
+}
+
+// Assert compares canonical JSON with the golden file with the default
+// options. See Options.Assert for details.
+func Assert(t *testing.T, name string, got []byte) bool {
+	t.Helper()
+
+	return Options{}.Assert(t, name, got)
+}
+
+// AssertFields compares fields with the golden file with the default options.
+// See Options.AssertFields for details.
+func AssertFields(t *testing.T, name string, fields ...zap.Field) bool {
+	t.Helper()
+
+	return Options{}.AssertFields(t, name, fields...)
+}
+
+// AssertValue compares a recorded value with the golden file with the default
+// options. See Options.AssertValue for details.
+func AssertValue(t *testing.T, name string, v zaprec.Value) bool {
+	t.Helper()
+
+	return Options{}.AssertValue(t, name, v)
+}
@@ -0,0 +1,118 @@
+package golden_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/internal/protolog"
+	"github.com/adzil/zapf/zaprec"
+	"github.com/adzil/zapf/zaprec/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEncodeFields(t *testing.T) {
+	type Context struct {
+		Options golden.Options
+		Input   []zap.Field
+		Expects string
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with sorted keys and normalized time": func(t *testing.T, tc *Context) {
+			tc.Input = []zap.Field{
+				zap.String("b", "second"),
+				zap.Time("time", time.Now()),
+				zap.Object("a", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					enc.AddDuration("duration", time.Second)
+					enc.AddUint64("big", 18446744073709551615)
+
+					return nil
+				})),
+			}
+
+			tc.Expects = `{
+  "a": {
+    "big": 18446744073709551615,
+    "duration": "1s"
+  },
+  "b": "second",
+  "time": "<normalized>"
+}
+`
+		},
+
+		"with normalized keys": func(t *testing.T, tc *Context) {
+			tc.Options.Normalize = []string{"id"}
+			tc.Input = []zap.Field{
+				zap.Array("items", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+					return enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+						enc.AddString("id", "random")
+						enc.AddString("name", "item")
+
+						return nil
+					}))
+				})),
+			}
+
+			tc.Expects = `{
+  "items": [
+    {
+      "id": "<normalized>",
+      "name": "item"
+    }
+  ]
+}
+`
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			b, err := tc.Options.EncodeFields(tc.Input...)
+			require.NoError(t, err, "encode fields should return nil error")
+			assert.Equal(t, tc.Expects, string(b), "encoded fields should match")
+		})
+	}
+}
+
+func TestAssertFields(t *testing.T) {
+	msg := &marshalerpb.Marshaler{
+		Map:    map[string]string{"b": "2", "a": "1"},
+		Array:  []string{"hello", "world"},
+		Enum:   marshalerpb.Choice_CHOICE_TWO,
+		Uint64: 18446744073709551615,
+		Payload: &marshalerpb.Marshaler_Message{
+			Message: &marshalerpb.Message{Text: "hello"},
+		},
+	}
+
+	golden.AssertFields(t, "message", zap.Object("message", protolog.MarshalerOf(msg)))
+}
+
+func TestAssertValue(t *testing.T) {
+	golden.AssertValue(t, "value", zaprec.Object{
+		"name": zaprec.String("zapf"),
+		"tags": zaprec.Array{zaprec.String("a"), zaprec.String("b")},
+	})
+}
+
+func TestAssert_Update(t *testing.T) {
+	t.Setenv(golden.UpdateEnv, "1")
+
+	opts := golden.Options{
+		Dir: t.TempDir(),
+	}
+
+	opts.Assert(t, "update", []byte("{}\n"))
+
+	b, err := os.ReadFile(filepath.Join(opts.Dir, "update.golden.json"))
+	require.NoError(t, err, "golden file should be written")
+	assert.Equal(t, "{}\n", string(b), "golden file should contain the output")
+}
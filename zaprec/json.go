@@ -0,0 +1,109 @@
+package zaprec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// jsonValue converts a recorded value into a value marshaled by encoding/json.
+// Unlike zapcore.JSONEncoder, durations are converted with their String method
+// and times into RFC 3339 strings in UTC, so they are readable in golden files.
+func jsonValue(v Value) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case Object:
+		return jsonObject(v)
+	case Namespace:
+		return jsonObject(v)
+	case Array:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			jv, err := jsonValue(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = jv
+		}
+
+		return result, nil
+	case String:
+		return string(v), nil
+	case ByteString:
+		return string(v), nil
+	case Binary:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case Bool:
+		return bool(v), nil
+	case Complex128:
+		return strconv.FormatComplex(complex128(v), 'g', -1, 128), nil
+	case Complex64:
+		return strconv.FormatComplex(complex128(v), 'g', -1, 64), nil
+	case Float64:
+		return float64(v), nil
+	case Float32:
+		return float32(v), nil
+	case Int:
+		return int(v), nil
+	case Int64:
+		return int64(v), nil
+	case Int32:
+		return int32(v), nil
+	case Int16:
+		return int16(v), nil
+	case Int8:
+		return int8(v), nil
+	case Uint:
+		return uint(v), nil
+	case Uint64:
+		return uint64(v), nil
+	case Uint32:
+		return uint32(v), nil
+	case Uint16:
+		return uint16(v), nil
+	case Uint8:
+		return uint8(v), nil
+	case Uintptr:
+		return uint64(v), nil
+	case Duration:
+		return time.Duration(v).String(), nil
+	case Time:
+		return time.Time(v).UTC().Format(time.RFC3339Nano), nil
+	case Reflected:
+		return v.Value, nil
+	}
+
+	return nil, fmt.Errorf("cannot convert recorded value of type %T", v)
+}
+
+func jsonObject[M ~map[string]Value](m M) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(m))
+	for k, elem := range m {
+		jv, err := jsonValue(elem)
+		if err != nil {
+			return nil, err
+		}
+
+		result[k] = jv
+	}
+
+	return result, nil
+}
+
+// JSON converts a recorded value into canonical JSON with sorted object keys
+// and two spaces indentation, so recorded trees can be diffed and stored as
+// golden files. Durations are converted into strings and times into RFC 3339
+// strings in UTC. It returns an error for NaN and infinite floats, which
+// zapcore.JSONEncoder writes as strings instead.
+func JSON(v Value) ([]byte, error) {
+	jv, err := jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(jv, "", "  ")
+}
@@ -0,0 +1,79 @@
+package zaprec_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	type Context struct {
+		Input   zaprec.Value
+		Expects string
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with nested values": func(t *testing.T, tc *Context) {
+			tc.Input = zaprec.Object{
+				"string": zaprec.String("test"),
+				"array": zaprec.Array{
+					zaprec.Int(1),
+					zaprec.Float32(1.5),
+					zaprec.Bool(true),
+				},
+				"binary":    zaprec.Binary("binary"),
+				"complex":   zaprec.Complex128(1 + 2i),
+				"duration":  zaprec.Duration(time.Second),
+				"time":      zaprec.Time(time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("WIB", 7*60*60))),
+				"reflected": zaprec.Reflected{Value: map[string]int{"b": 2, "a": 1}},
+				"namespace": zaprec.Namespace{
+					"uint64": zaprec.Uint64(18446744073709551615),
+				},
+			}
+
+			tc.Expects = `{
+  "array": [
+    1,
+    1.5,
+    true
+  ],
+  "binary": "YmluYXJ5",
+  "complex": "(1+2i)",
+  "duration": "1s",
+  "namespace": {
+    "uint64": 18446744073709551615
+  },
+  "reflected": {
+    "a": 1,
+    "b": 2
+  },
+  "string": "test",
+  "time": "2024-01-01T20:04:05.000000006Z"
+}`
+		},
+
+		"with nil value": func(t *testing.T, tc *Context) {
+			tc.Expects = "null"
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			b, err := zaprec.JSON(tc.Input)
+			require.NoError(t, err, "json should return nil error")
+			assert.Equal(t, tc.Expects, string(b), "canonical json should match")
+		})
+	}
+}
+
+func TestJSON_NaN(t *testing.T) {
+	_, err := zaprec.JSON(zaprec.Object{
+		"nan": zaprec.Float64(math.NaN()),
+	})
+	assert.Error(t, err, "json should return error for NaN")
+}
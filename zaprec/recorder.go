@@ -0,0 +1,294 @@
+package zaprec
+
+import (
+	"time"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"go.uber.org/zap/zapcore"
+)
+
+// ObjectEncoder is a zapcore.ObjectEncoder that records each added field as a
+// Value.
+type ObjectEncoder struct {
+	ns fieldenc.Namespace[Value]
+}
+
+var _ zapcore.ObjectEncoder = (*ObjectEncoder)(nil)
+
+// NewObjectEncoder constructs an empty ObjectEncoder.
+func NewObjectEncoder() *ObjectEncoder {
+	return &ObjectEncoder{}
+}
+
+// object converts the fields into an Object. It returns nil if there is no
+// field.
+func object(kvs []fieldenc.KeyValue[Value]) Object {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	result := make(Object, len(kvs))
+	for _, kv := range kvs {
+		result[kv.Key] = kv.Value
+	}
+
+	return result
+}
+
+// Result returns the recorded fields. It returns nil if no field is added.
+func (enc *ObjectEncoder) Result() Object {
+	return object(enc.ns.KeyValues(func(kvs []fieldenc.KeyValue[Value]) Value {
+		return Namespace(object(kvs))
+	}))
+}
+
+func (enc *ObjectEncoder) addValue(k string, v Value) {
+	enc.ns.Add(k, v)
+}
+
+func (enc *ObjectEncoder) AddObject(key string, ms zapcore.ObjectMarshaler) error {
+	nenc := NewObjectEncoder()
+	if err := ms.MarshalLogObject(nenc); err != nil {
+		return err
+	}
+
+	enc.addValue(key, nenc.Result())
+
+	return nil
+}
+
+func (enc *ObjectEncoder) AddArray(key string, ms zapcore.ArrayMarshaler) error {
+	nenc := NewArrayEncoder()
+	if err := ms.MarshalLogArray(nenc); err != nil {
+		return err
+	}
+
+	enc.addValue(key, nenc.Result())
+
+	return nil
+}
+
+func (enc *ObjectEncoder) AddBinary(key string, b []byte) {
+	enc.addValue(key, Binary(b))
+}
+
+func (enc *ObjectEncoder) AddByteString(key string, b []byte) {
+	enc.addValue(key, ByteString(b))
+}
+
+func (enc *ObjectEncoder) AddBool(key string, b bool) {
+	enc.addValue(key, Bool(b))
+}
+
+func (enc *ObjectEncoder) AddComplex128(key string, c complex128) {
+	enc.addValue(key, Complex128(c))
+}
+
+func (enc *ObjectEncoder) AddComplex64(key string, c complex64) {
+	enc.addValue(key, Complex64(c))
+}
+
+func (enc *ObjectEncoder) AddDuration(key string, d time.Duration) {
+	enc.addValue(key, Duration(d))
+}
+
+func (enc *ObjectEncoder) AddFloat64(key string, f float64) {
+	enc.addValue(key, Float64(f))
+}
+
+func (enc *ObjectEncoder) AddFloat32(key string, f float32) {
+	enc.addValue(key, Float32(f))
+}
+
+func (enc *ObjectEncoder) AddInt(key string, i int) {
+	enc.addValue(key, Int(i))
+}
+
+func (enc *ObjectEncoder) AddInt64(key string, i int64) {
+	enc.addValue(key, Int64(i))
+}
+
+func (enc *ObjectEncoder) AddInt32(key string, i int32) {
+	enc.addValue(key, Int32(i))
+}
+
+func (enc *ObjectEncoder) AddInt16(key string, i int16) {
+	enc.addValue(key, Int16(i))
+}
+
+func (enc *ObjectEncoder) AddInt8(key string, i int8) {
+	enc.addValue(key, Int8(i))
+}
+
+func (enc *ObjectEncoder) AddString(key, s string) {
+	enc.addValue(key, String(s))
+}
+
+func (enc *ObjectEncoder) AddTime(key string, t time.Time) {
+	enc.addValue(key, Time(t))
+}
+
+func (enc *ObjectEncoder) AddUint(key string, u uint) {
+	enc.addValue(key, Uint(u))
+}
+
+func (enc *ObjectEncoder) AddUint64(key string, u uint64) {
+	enc.addValue(key, Uint64(u))
+}
+
+func (enc *ObjectEncoder) AddUint32(key string, u uint32) {
+	enc.addValue(key, Uint32(u))
+}
+
+func (enc *ObjectEncoder) AddUint16(key string, u uint16) {
+	enc.addValue(key, Uint16(u))
+}
+
+func (enc *ObjectEncoder) AddUint8(key string, u uint8) {
+	enc.addValue(key, Uint8(u))
+}
+
+func (enc *ObjectEncoder) AddUintptr(key string, u uintptr) {
+	enc.addValue(key, Uintptr(u))
+}
+
+func (enc *ObjectEncoder) AddReflected(key string, v interface{}) error {
+	enc.addValue(key, Reflected{Value: v})
+
+	return nil
+}
+
+func (enc *ObjectEncoder) OpenNamespace(key string) {
+	enc.ns.Open(key)
+}
+
+// ArrayEncoder is a zapcore.ArrayEncoder that records each appended element
+// as a Value.
+type ArrayEncoder struct {
+	result Array
+}
+
+var _ zapcore.ArrayEncoder = (*ArrayEncoder)(nil)
+
+// NewArrayEncoder constructs an empty ArrayEncoder.
+func NewArrayEncoder() *ArrayEncoder {
+	return &ArrayEncoder{}
+}
+
+// Result returns the recorded elements. It returns nil if no element is
+// appended.
+func (enc *ArrayEncoder) Result() Array {
+	return enc.result
+}
+
+func (enc *ArrayEncoder) appendValue(v Value) {
+	enc.result = append(enc.result, v)
+}
+
+func (enc *ArrayEncoder) AppendObject(ms zapcore.ObjectMarshaler) error {
+	nenc := NewObjectEncoder()
+	if err := ms.MarshalLogObject(nenc); err != nil {
+		return err
+	}
+
+	enc.appendValue(nenc.Result())
+
+	return nil
+}
+
+func (enc *ArrayEncoder) AppendArray(ms zapcore.ArrayMarshaler) error {
+	nenc := NewArrayEncoder()
+	if err := ms.MarshalLogArray(nenc); err != nil {
+		return err
+	}
+
+	enc.appendValue(nenc.Result())
+
+	return nil
+}
+
+func (enc *ArrayEncoder) AppendBool(b bool) {
+	enc.appendValue(Bool(b))
+}
+
+func (enc *ArrayEncoder) AppendByteString(b []byte) {
+	enc.appendValue(ByteString(b))
+}
+
+func (enc *ArrayEncoder) AppendComplex128(c complex128) {
+	enc.appendValue(Complex128(c))
+}
+
+func (enc *ArrayEncoder) AppendComplex64(c complex64) {
+	enc.appendValue(Complex64(c))
+}
+
+func (enc *ArrayEncoder) AppendDuration(d time.Duration) {
+	enc.appendValue(Duration(d))
+}
+
+func (enc *ArrayEncoder) AppendFloat64(f float64) {
+	enc.appendValue(Float64(f))
+}
+
+func (enc *ArrayEncoder) AppendFloat32(f float32) {
+	enc.appendValue(Float32(f))
+}
+
+func (enc *ArrayEncoder) AppendInt(i int) {
+	enc.appendValue(Int(i))
+}
+
+func (enc *ArrayEncoder) AppendInt64(i int64) {
+	enc.appendValue(Int64(i))
+}
+
+func (enc *ArrayEncoder) AppendInt32(i int32) {
+	enc.appendValue(Int32(i))
+}
+
+func (enc *ArrayEncoder) AppendInt16(i int16) {
+	enc.appendValue(Int16(i))
+}
+
+func (enc *ArrayEncoder) AppendInt8(i int8) {
+	enc.appendValue(Int8(i))
+}
+
+func (enc *ArrayEncoder) AppendString(s string) {
+	enc.appendValue(String(s))
+}
+
+func (enc *ArrayEncoder) AppendTime(t time.Time) {
+	enc.appendValue(Time(t))
+}
+
+func (enc *ArrayEncoder) AppendUint(u uint) {
+	enc.appendValue(Uint(u))
+}
+
+func (enc *ArrayEncoder) AppendUint64(u uint64) {
+	enc.appendValue(Uint64(u))
+}
+
+func (enc *ArrayEncoder) AppendUint32(u uint32) {
+	enc.appendValue(Uint32(u))
+}
+
+func (enc *ArrayEncoder) AppendUint16(u uint16) {
+	enc.appendValue(Uint16(u))
+}
+
+func (enc *ArrayEncoder) AppendUint8(u uint8) {
+	enc.appendValue(Uint8(u))
+}
+
+func (enc *ArrayEncoder) AppendUintptr(u uintptr) {
+	enc.appendValue(Uintptr(u))
+}
+
+func (enc *ArrayEncoder) AppendReflected(v interface{}) error {
+	enc.appendValue(Reflected{Value: v})
+
+	return nil
+}
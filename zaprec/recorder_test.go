@@ -0,0 +1,210 @@
+package zaprec_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zaprec"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestObjectEncoder_Values(t *testing.T) {
+	now := time.Unix(1, 0)
+
+	expected := zaprec.Object{
+		"object": zaprec.Object{
+			"string": zaprec.String("test"),
+		},
+		"array": zaprec.Array{
+			zaprec.String("test"),
+		},
+		"binary":     zaprec.Binary("binary"),
+		"byteString": zaprec.ByteString("byte string"),
+		"bool":       zaprec.Bool(true),
+		"complex128": zaprec.Complex128(1 + 2i),
+		"complex64":  zaprec.Complex64(3 + 4i),
+		"duration":   zaprec.Duration(time.Second),
+		"float64":    zaprec.Float64(-2.0),
+		"float32":    zaprec.Float32(1.0),
+		"int":        zaprec.Int(-1),
+		"int64":      zaprec.Int64(-4),
+		"int32":      zaprec.Int32(-3),
+		"int16":      zaprec.Int16(-2),
+		"int8":       zaprec.Int8(-1),
+		"time":       zaprec.Time(now),
+		"uint":       zaprec.Uint(1),
+		"uint64":     zaprec.Uint64(6),
+		"uint32":     zaprec.Uint32(5),
+		"uint16":     zaprec.Uint16(4),
+		"uint8":      zaprec.Uint8(3),
+		"uintptr":    zaprec.Uintptr(2),
+		"reflected":  zaprec.Reflected{Value: []int{1}},
+		"namespace": zaprec.Namespace{
+			"string": zaprec.String("namespaced"),
+			"nested": zaprec.Namespace{
+				"bool": zaprec.Bool(false),
+			},
+		},
+	}
+
+	enc := zaprec.NewObjectEncoder()
+
+	err := enc.AddObject("object", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("string", "test")
+
+		return nil
+	}))
+	assert.NoError(t, err, "add object should return no error")
+
+	err = enc.AddArray("array", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendString("test")
+
+		return nil
+	}))
+	assert.NoError(t, err, "add array should return no error")
+
+	enc.AddBinary("binary", []byte("binary"))
+	enc.AddByteString("byteString", []byte("byte string"))
+	enc.AddBool("bool", true)
+	enc.AddComplex128("complex128", 1+2i)
+	enc.AddComplex64("complex64", 3+4i)
+	enc.AddDuration("duration", time.Second)
+	enc.AddFloat64("float64", -2.0)
+	enc.AddFloat32("float32", 1.0)
+	enc.AddInt("int", -1)
+	enc.AddInt64("int64", -4)
+	enc.AddInt32("int32", -3)
+	enc.AddInt16("int16", -2)
+	enc.AddInt8("int8", -1)
+	enc.AddTime("time", now)
+	enc.AddUint("uint", 1)
+	enc.AddUint64("uint64", 6)
+	enc.AddUint32("uint32", 5)
+	enc.AddUint16("uint16", 4)
+	enc.AddUint8("uint8", 3)
+	enc.AddUintptr("uintptr", 2)
+
+	err = enc.AddReflected("reflected", []int{1})
+	assert.NoError(t, err, "add reflected should return no error")
+
+	enc.OpenNamespace("namespace")
+	enc.AddString("string", "namespaced")
+	enc.OpenNamespace("nested")
+	enc.AddBool("bool", false)
+
+	assert.Equal(t, expected, enc.Result(), "encode result should equal with the expected result")
+}
+
+func TestObjectEncoder_AddErrors(t *testing.T) {
+	enc := zaprec.NewObjectEncoder()
+
+	expectedError := errors.New("test error")
+
+	err := enc.AddObject("object", zapcore.ObjectMarshalerFunc(func(_ zapcore.ObjectEncoder) error {
+		return expectedError
+	}))
+	assert.ErrorIs(t, err, expectedError, "add object should return expected error")
+
+	err = enc.AddArray("array", zapcore.ArrayMarshalerFunc(func(_ zapcore.ArrayEncoder) error {
+		return expectedError
+	}))
+	assert.ErrorIs(t, err, expectedError, "add array should return expected error")
+
+	assert.Nil(t, enc.Result(), "encoder should be empty")
+}
+
+func TestArrayEncoder_Values(t *testing.T) {
+	now := time.Unix(1, 0)
+
+	expected := zaprec.Array{
+		zaprec.Object{
+			"string": zaprec.String("test"),
+		},
+		zaprec.Array{
+			zaprec.String("test"),
+		},
+		zaprec.Bool(true),
+		zaprec.ByteString("byte string"),
+		zaprec.Complex128(1 + 2i),
+		zaprec.Complex64(3 + 4i),
+		zaprec.Duration(time.Second),
+		zaprec.Float64(-2.0),
+		zaprec.Float32(1.0),
+		zaprec.Int(-1),
+		zaprec.Int64(-4),
+		zaprec.Int32(-3),
+		zaprec.Int16(-2),
+		zaprec.Int8(-1),
+		zaprec.String("string"),
+		zaprec.Time(now),
+		zaprec.Uint(1),
+		zaprec.Uint64(6),
+		zaprec.Uint32(5),
+		zaprec.Uint16(4),
+		zaprec.Uint8(3),
+		zaprec.Uintptr(2),
+		zaprec.Reflected{Value: map[string]int{"a": 1}},
+	}
+
+	enc := zaprec.NewArrayEncoder()
+
+	err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("string", "test")
+
+		return nil
+	}))
+	assert.NoError(t, err, "append object should return no error")
+
+	err = enc.AppendArray(zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendString("test")
+
+		return nil
+	}))
+	assert.NoError(t, err, "append array should return no error")
+
+	enc.AppendBool(true)
+	enc.AppendByteString([]byte("byte string"))
+	enc.AppendComplex128(1 + 2i)
+	enc.AppendComplex64(3 + 4i)
+	enc.AppendDuration(time.Second)
+	enc.AppendFloat64(-2.0)
+	enc.AppendFloat32(1.0)
+	enc.AppendInt(-1)
+	enc.AppendInt64(-4)
+	enc.AppendInt32(-3)
+	enc.AppendInt16(-2)
+	enc.AppendInt8(-1)
+	enc.AppendString("string")
+	enc.AppendTime(now)
+	enc.AppendUint(1)
+	enc.AppendUint64(6)
+	enc.AppendUint32(5)
+	enc.AppendUint16(4)
+	enc.AppendUint8(3)
+	enc.AppendUintptr(2)
+
+	err = enc.AppendReflected(map[string]int{"a": 1})
+	assert.NoError(t, err, "append reflected should return no error")
+
+	assert.Equal(t, expected, enc.Result(), "encode result should equal with the expected result")
+}
+
+func TestArrayEncoder_AddErrors(t *testing.T) {
+	enc := zaprec.NewArrayEncoder()
+
+	expectedError := errors.New("test error")
+
+	err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(_ zapcore.ObjectEncoder) error {
+		return expectedError
+	}))
+	assert.ErrorIs(t, err, expectedError, "append object should return expected error")
+
+	err = enc.AppendArray(zapcore.ArrayMarshalerFunc(func(_ zapcore.ArrayEncoder) error {
+		return expectedError
+	}))
+	assert.ErrorIs(t, err, expectedError, "append array should return expected error")
+
+	assert.Nil(t, enc.Result(), "encoder should be empty")
+}
@@ -0,0 +1,94 @@
+package zaprec
+
+import (
+	"time"
+)
+
+// Value is a value recorded by ObjectEncoder or ArrayEncoder. Each
+// zapcore.ObjectEncoder and zapcore.ArrayEncoder method records its own value
+// type, so expected trees can assert the exact encoder method being called.
+type Value interface {
+	isValue()
+}
+
+type (
+	// Object is recorded by AddObject, AppendObject and the encoder itself.
+	Object map[string]Value
+	// Namespace holds the fields added after OpenNamespace is called.
+	Namespace map[string]Value
+	// Array is recorded by AddArray and AppendArray.
+	Array []Value
+	// String is recorded by AddString and AppendString.
+	String string
+	// ByteString is recorded by AddByteString and AppendByteString.
+	ByteString string
+	// Binary is recorded by AddBinary.
+	Binary []byte
+	// Bool is recorded by AddBool and AppendBool.
+	Bool bool
+	// Complex128 is recorded by AddComplex128 and AppendComplex128.
+	Complex128 complex128
+	// Complex64 is recorded by AddComplex64 and AppendComplex64.
+	Complex64 complex64
+	// Float64 is recorded by AddFloat64 and AppendFloat64.
+	Float64 float64
+	// Float32 is recorded by AddFloat32 and AppendFloat32.
+	Float32 float32
+	// Int is recorded by AddInt and AppendInt.
+	Int int
+	// Int64 is recorded by AddInt64 and AppendInt64.
+	Int64 int64
+	// Int32 is recorded by AddInt32 and AppendInt32.
+	Int32 int32
+	// Int16 is recorded by AddInt16 and AppendInt16.
+	Int16 int16
+	// Int8 is recorded by AddInt8 and AppendInt8.
+	Int8 int8
+	// Uint is recorded by AddUint and AppendUint.
+	Uint uint
+	// Uint64 is recorded by AddUint64 and AppendUint64.
+	Uint64 uint64
+	// Uint32 is recorded by AddUint32 and AppendUint32.
+	Uint32 uint32
+	// Uint16 is recorded by AddUint16 and AppendUint16.
+	Uint16 uint16
+	// Uint8 is recorded by AddUint8 and AppendUint8.
+	Uint8 uint8
+	// Uintptr is recorded by AddUintptr and AppendUintptr.
+	Uintptr uintptr
+	// Duration is recorded by AddDuration and AppendDuration.
+	Duration time.Duration
+	// Time is recorded by AddTime and AppendTime.
+	Time time.Time
+	// Reflected is recorded by AddReflected and AppendReflected with the
+	// original value.
+	Reflected struct {
+		Value interface{}
+	}
+)
+
+func (Object) isValue()     {}
+func (Namespace) isValue()  {}
+func (Array) isValue()      {}
+func (String) isValue()     {}
+func (ByteString) isValue() {}
+func (Binary) isValue()     {}
+func (Bool) isValue()       {}
+func (Complex128) isValue() {}
+func (Complex64) isValue()  {}
+func (Float64) isValue()    {}
+func (Float32) isValue()    {}
+func (Int) isValue()        {}
+func (Int64) isValue()      {}
+func (Int32) isValue()      {}
+func (Int16) isValue()      {}
+func (Int8) isValue()       {}
+func (Uint) isValue()       {}
+func (Uint64) isValue()     {}
+func (Uint32) isValue()     {}
+func (Uint16) isValue()     {}
+func (Uint8) isValue()      {}
+func (Uintptr) isValue()    {}
+func (Duration) isValue()   {}
+func (Time) isValue()       {}
+func (Reflected) isValue()  {}
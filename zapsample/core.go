@@ -0,0 +1,74 @@
+package zapsample
+
+import (
+	"github.com/adzil/zapf/internal/wrapcore"
+	"go.uber.org/zap/zapcore"
+)
+
+type core struct {
+	zapcore.Core
+}
+
+// NewCore wraps a zapcore.Core to decide the fields returned by Sampler.Field
+// once per entry, before the entry is written to the wrapped core. The
+// included fields are written with their own type, so type-aware cores such as
+// zapecs and zapgcp handle them as if they were not sampled, and every core of
+// a zapcore.NewTee writes the same decision while taking a single token of the
+// rate limit. It should be the outermost core. The fields added with With are
+// decided once when they are added.
+func NewCore(c zapcore.Core) zapcore.Core {
+	return &core{
+		Core: c,
+	}
+}
+
+// decide returns the fields with each field returned by Sampler.Field replaced
+// by the field it wraps if the entry includes it, or removed otherwise.
+func decide(fields []zapcore.Field) []zapcore.Field {
+	var result []zapcore.Field
+
+	for i, f := range fields {
+		m, ok := f.Interface.(sampledMarshaler)
+		if f.Type != zapcore.InlineMarshalerType || !ok {
+			if result != nil {
+				result = append(result, f)
+			}
+
+			continue
+		}
+
+		if result == nil {
+			result = make([]zapcore.Field, i, len(fields))
+			copy(result, fields[:i])
+		}
+
+		if m.Sampler.include(m.Field.Key) {
+			result = append(result, m.Field)
+		}
+	}
+
+	if result == nil {
+		return fields
+	}
+
+	return result
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		Core: c.Core.With(decide(fields)),
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return wrapcore.Check(c, c.Core, ent, ce)
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(c.Rewrite(ent, fields))
+}
+
+// Rewrite decides the sampled fields of the entry.
+func (c *core) Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	return ent, decide(fields)
+}
@@ -0,0 +1,69 @@
+package zapsample_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/adzil/zapf/zapecs"
+	"github.com/adzil/zapf/zapsample"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewCore_Tee(t *testing.T) {
+	s := zapsample.Options{PerSecond: 1}.NewSampler()
+
+	obsA, logsA := observer.New(zapcore.InfoLevel)
+	obsB, logsB := observer.New(zapcore.InfoLevel)
+	logger := zap.New(zapsample.NewCore(zapcore.NewTee(obsA, obsB)))
+
+	for i := 0; i < 2; i++ {
+		logger.Info("hello", s.Field(context.Background(), zap.String("body", "a")))
+	}
+
+	for _, logs := range []*observer.ObservedLogs{logsA, logsB} {
+		require.Equal(t, 2, logs.Len(), "every entry should be written")
+		assert.Equal(t, map[string]interface{}{"body": "a"}, logs.All()[0].ContextMap(),
+			"first entry should include the field in every core")
+		assert.Empty(t, logs.All()[1].ContextMap(), "second entry should be limited in every core")
+	}
+}
+
+func TestNewCore_With(t *testing.T) {
+	s := zapsample.Options{PerSecond: 1}.NewSampler()
+
+	obs, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(zapsample.NewCore(obs)).
+		With(s.Field(context.Background(), zap.String("body", "a")))
+
+	logger.Info("first")
+	logger.Info("second", s.Field(context.Background(), zap.String("body", "b")))
+
+	require.Equal(t, 2, logs.Len(), "every entry should be written")
+	assert.Equal(t, map[string]interface{}{"body": "a"}, logs.All()[0].ContextMap(),
+		"field added with With should be decided once")
+	assert.Equal(t, map[string]interface{}{"body": "a"}, logs.All()[1].ContextMap(),
+		"entry field should be limited")
+}
+
+func TestNewCore_ZapECS(t *testing.T) {
+	var out bytes.Buffer
+
+	enc := zapcore.NewJSONEncoder(zapecs.EncoderConfig())
+	ecs := zapecs.Options{Namespace: "payload"}.NewCore(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.InfoLevel))
+	logger := zap.New(zapsample.NewCore(ecs))
+
+	s := zapsample.Options{Fraction: 1}.NewSampler()
+	logger.Info("hello", s.Field(context.Background(), zap.Strings("tags", []string{"a"})))
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry), "entry should be valid json")
+
+	assert.Equal(t, []interface{}{"a"}, entry["payload.tags"], "sampled array field should be namespaced")
+	assert.NotContains(t, entry, "tags", "sampled array field should not be written as is")
+}
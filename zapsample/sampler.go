@@ -0,0 +1,146 @@
+// Package zapsample provides field wrappers that include expensive fields,
+// such as Protobuf messages constructed with zapproto, only in a fraction of
+// the entries, and a core deciding them once per entry.
+package zapsample
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Options configures the Sampler constructed by Options.NewSampler. The field
+// is included only when every configured condition is met.
+type Options struct {
+	// Fraction is the fraction of the entries including the field, between 0
+	// and 1. Every entry includes the field when it is zero, so there is no
+	// fraction including none of them; such a field should not be added.
+	Fraction float64
+	// PerSecond limits the number of the entries including a field of the
+	// same key per second, with bursts of up to PerSecond entries. The entries
+	// are not limited when it is not set.
+	PerSecond int
+	// Sampled includes the field only when the trace.SpanContext of the
+	// context is sampled, keeping the logged payloads consistent with the
+	// recorded traces.
+	Sampled bool
+}
+
+// bucket is a token bucket limiting the entries of a field key.
+type bucket struct {
+	Tokens float64
+	Last   time.Time
+}
+
+// Sampler decides whether the entries include a field. It is safe for
+// concurrent use.
+type Sampler struct {
+	opts Options
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewSampler constructs a Sampler with the options.
+func (opts Options) NewSampler() *Sampler {
+	return &Sampler{
+		opts:    opts,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token of the bucket of the key.
+func (s *Sampler) allow(key string) bool {
+	now := time.Now()
+	limit := float64(s.opts.PerSecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{
+			Tokens: limit,
+			Last:   now,
+		}
+		s.buckets[key] = b
+	}
+
+	b.Tokens += now.Sub(b.Last).Seconds() * limit
+	if b.Tokens > limit {
+		b.Tokens = limit
+	}
+
+	b.Last = now
+
+	if b.Tokens < 1 {
+		return false
+	}
+
+	b.Tokens--
+
+	return true
+}
+
+// include reports whether an entry includes the field of the key, taking a
+// token of the rate limit if it does.
+func (s *Sampler) include(key string) bool {
+	if s.opts.Fraction > 0 && rand.Float64() >= s.opts.Fraction {
+		return false
+	}
+
+	return s.opts.PerSecond <= 0 || s.allow(key)
+}
+
+// sampledMarshaler adds the field when the sampler includes it at the time
+// the entry is encoded. NewCore replaces it with the field before encoding.
+type sampledMarshaler struct {
+	Sampler *Sampler
+	Field   zap.Field
+}
+
+func (m sampledMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if m.Sampler.include(m.Field.Key) {
+		m.Field.AddTo(enc)
+	}
+
+	return nil
+}
+
+// Field returns a field that includes f if the entry should include it. The
+// fraction and the rate limit are decided by NewCore once per written entry,
+// so the entries that are disabled or dropped by the core do not consume the
+// rate limit. Without NewCore, the field is an inline field decided each time
+// it is encoded: the type of f is hidden from type-aware cores such as the
+// Namespace of zapecs, and each core of a zapcore.NewTee decides separately
+// and takes its own token of the rate limit. The span context is checked right
+// away, returning a skipped field if it is not sampled.
+func (s *Sampler) Field(ctx context.Context, f zap.Field) zap.Field {
+	if s.opts.Sampled && !trace.SpanContextFromContext(ctx).IsSampled() {
+		return zap.Skip()
+	}
+
+	if s.opts.Fraction <= 0 && s.opts.PerSecond <= 0 {
+		return f
+	}
+
+	return zap.Inline(sampledMarshaler{
+		Sampler: s,
+		Field:   f,
+	})
+}
+
+// Sampled returns the field if the trace.SpanContext of the context is
+// sampled, or a skipped field otherwise.
+func Sampled(ctx context.Context, f zap.Field) zap.Field {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return zap.Skip()
+	}
+
+	return f
+}
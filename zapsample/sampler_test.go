@@ -0,0 +1,158 @@
+package zapsample_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/adzil/zapf/zapsample"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func spanContext(flags trace.TraceFlags) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: flags,
+	}))
+}
+
+func TestSampler_Field(t *testing.T) {
+	type Context struct {
+		Options zapsample.Options
+		Context context.Context
+		Fields  []zap.Field
+		Expects []map[string]interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"without options": func(t *testing.T, tc *Context) {
+			tc.Fields = []zap.Field{zap.String("body", "a"), zap.String("body", "b")}
+
+			tc.Expects = []map[string]interface{}{{"body": "a"}, {"body": "b"}}
+		},
+
+		"with per second limit": func(t *testing.T, tc *Context) {
+			tc.Options.PerSecond = 2
+			tc.Fields = []zap.Field{
+				zap.String("body", "a"),
+				zap.String("body", "b"),
+				zap.String("other", "c"),
+				zap.String("body", "d"),
+			}
+
+			tc.Expects = []map[string]interface{}{
+				{"body": "a"},
+				{"body": "b"},
+				{"other": "c"},
+				{},
+			}
+		},
+
+		"with sampled span context": func(t *testing.T, tc *Context) {
+			tc.Options.Sampled = true
+			tc.Context = spanContext(trace.FlagsSampled)
+			tc.Fields = []zap.Field{zap.String("body", "a")}
+
+			tc.Expects = []map[string]interface{}{{"body": "a"}}
+		},
+
+		"with unsampled span context": func(t *testing.T, tc *Context) {
+			tc.Options.Sampled = true
+			tc.Context = spanContext(0)
+			tc.Fields = []zap.Field{zap.String("body", "a")}
+
+			tc.Expects = []map[string]interface{}{{}}
+		},
+
+		"without span context": func(t *testing.T, tc *Context) {
+			tc.Options.Sampled = true
+			tc.Fields = []zap.Field{zap.String("body", "a")}
+
+			tc.Expects = []map[string]interface{}{{}}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{
+				Context: context.Background(),
+			}
+			v(t, tc)
+
+			s := tc.Options.NewSampler()
+
+			encoded := make([]map[string]interface{}, 0, len(tc.Fields))
+			for _, f := range tc.Fields {
+				enc := zapcore.NewMapObjectEncoder()
+				s.Field(tc.Context, f).AddTo(enc)
+
+				encoded = append(encoded, enc.Fields)
+			}
+
+			assert.Equal(t, tc.Expects, encoded, "encoded fields should match")
+		})
+	}
+}
+
+func TestSampler_Field_Fraction(t *testing.T) {
+	s := zapsample.Options{
+		Fraction: 0.25,
+	}.NewSampler()
+
+	const n = 10000
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		included int
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			enc := zapcore.NewMapObjectEncoder()
+			s.Field(context.Background(), zap.String("body", "a")).AddTo(enc)
+
+			if _, ok := enc.Fields["body"]; ok {
+				mu.Lock()
+				included++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.InDelta(t, n/4, included, n/20, "included fields should match the fraction")
+}
+
+func TestSampler_Field_DisabledEntry(t *testing.T) {
+	s := zapsample.Options{
+		PerSecond: 1,
+	}.NewSampler()
+
+	var out bytes.Buffer
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey: "msg",
+	})
+	logger := zap.New(zapcore.NewCore(enc, zapcore.AddSync(&out), zapcore.InfoLevel))
+
+	logger.Debug("disabled", s.Field(context.Background(), zap.String("body", "a")))
+	logger.Info("enabled", s.Field(context.Background(), zap.String("body", "b")))
+
+	assert.Equal(t, `{"msg":"enabled","body":"b"}`+"\n", out.String(), "disabled entry should not consume the rate limit")
+}
+
+func TestSampled(t *testing.T) {
+	f := zap.String("body", "a")
+
+	assert.Equal(t, f, zapsample.Sampled(spanContext(trace.FlagsSampled), f), "field should be included")
+	assert.Equal(t, zap.Skip(), zapsample.Sampled(spanContext(0), f), "field should be skipped")
+}
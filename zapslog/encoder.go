@@ -0,0 +1,99 @@
+package zapslog
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+)
+
+// valueConverter converts the encoded fields into slog.Value. Nested objects
+// and namespaces are converted into group values.
+type valueConverter struct{}
+
+func toAttrs(kvs []fieldenc.KeyValue[slog.Value]) []slog.Attr {
+	result := make([]slog.Attr, len(kvs))
+	for i, kv := range kvs {
+		result[i] = slog.Attr{Key: kv.Key, Value: kv.Value}
+	}
+
+	return result
+}
+
+// groupMap converts group attributes into a map so it can be serialized
+// inside arrays, which slog has no value kind for.
+func groupMap(attrs []slog.Attr) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			m[attr.Key] = groupMap(attr.Value.Group())
+
+			continue
+		}
+
+		m[attr.Key] = attr.Value.Any()
+	}
+
+	return m
+}
+
+func (valueConverter) Object(kvs []fieldenc.KeyValue[slog.Value]) slog.Value {
+	return slog.GroupValue(toAttrs(kvs)...)
+}
+
+// Array converts the elements into Go values, with nested objects converted
+// into maps.
+func (valueConverter) Array(vs []slog.Value) slog.Value {
+	values := make([]interface{}, len(vs))
+	for i, v := range vs {
+		if v.Kind() == slog.KindGroup {
+			values[i] = groupMap(v.Group())
+
+			continue
+		}
+
+		values[i] = v.Any()
+	}
+
+	return slog.AnyValue(values)
+}
+
+func (valueConverter) Binary(b []byte) slog.Value {
+	return slog.AnyValue(b)
+}
+
+func (valueConverter) Bool(b bool) slog.Value {
+	return slog.BoolValue(b)
+}
+
+func (valueConverter) Duration(d time.Duration) slog.Value {
+	return slog.DurationValue(d)
+}
+
+func (valueConverter) Float64(f float64) slog.Value {
+	return slog.Float64Value(f)
+}
+
+func (valueConverter) Int64(i int64) slog.Value {
+	return slog.Int64Value(i)
+}
+
+func (valueConverter) String(s string) slog.Value {
+	return slog.StringValue(s)
+}
+
+func (valueConverter) Time(t time.Time) slog.Value {
+	return slog.TimeValue(t)
+}
+
+func (valueConverter) Uint64(u uint64) slog.Value {
+	return slog.Uint64Value(u)
+}
+
+func (valueConverter) Reflected(v interface{}) (slog.Value, error) {
+	return slog.AnyValue(v), nil
+}
+
+func newObjectEncoder() *fieldenc.ObjectEncoder[slog.Value] {
+	return fieldenc.NewObjectEncoder[slog.Value](valueConverter{})
+}
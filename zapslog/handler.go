@@ -0,0 +1,217 @@
+package zapslog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/adzil/zapf/internal/protolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+)
+
+// Options configures the slog.Handler constructed by NewHandler.
+type Options struct {
+	// LoggerName is set as the logger name of each entry.
+	LoggerName string
+	// AddSource adds the caller of each record to its entry.
+	AddSource bool
+}
+
+type handler struct {
+	core zapcore.Core
+	opts Options
+
+	// groups holds the groups opened by WithGroup that have no attributes
+	// added yet. Empty groups are omitted.
+	groups []string
+}
+
+// NewHandler constructs a slog.Handler that converts slog records into zap
+// entries and writes them into core. Attribute values constructed by Field,
+// Message, TypedMessage or Context and Protobuf messages are written as their
+// zap fields, so they are serialized with the same rules as zap loggers.
+func (opts Options) NewHandler(core zapcore.Core) slog.Handler {
+	return &handler{
+		core: core,
+		opts: opts,
+	}
+}
+
+// NewHandler constructs a slog.Handler with the default options. See
+// Options.NewHandler for details.
+func NewHandler(core zapcore.Core) slog.Handler {
+	return Options{}.NewHandler(core)
+}
+
+// Level converts a slog.Level into a zapcore.Level. Levels between the slog
+// predefined levels are rounded down.
+func Level(lvl slog.Level) zapcore.Level {
+	switch {
+	case lvl < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case lvl < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case lvl < slog.LevelError:
+		return zapcore.WarnLevel
+	}
+
+	return zapcore.ErrorLevel
+}
+
+func (h *handler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.core.Enabled(Level(lvl))
+}
+
+// fields converts the attributes into zap fields under the pending groups.
+func (h *handler) fields(attrs []slog.Attr) []zap.Field {
+	fields := make([]zap.Field, 0, len(h.groups)+len(attrs))
+	for _, group := range h.groups {
+		fields = append(fields, zap.Namespace(group))
+	}
+
+	for _, attr := range attrs {
+		fields = append(fields, attrField(attr))
+	}
+
+	return fields
+}
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      Level(r.Level),
+		Time:       r.Time,
+		LoggerName: h.opts.LoggerName,
+		Message:    r.Message,
+	}
+
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.EntryCaller{
+			Defined:  true,
+			PC:       frame.PC,
+			File:     frame.File,
+			Line:     frame.Line,
+			Function: frame.Function,
+		}
+	}
+
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if r.NumAttrs() > 0 {
+		attrs := make([]slog.Attr, 0, r.NumAttrs())
+		r.Attrs(func(attr slog.Attr) bool {
+			attrs = append(attrs, attr)
+
+			return true
+		})
+
+		fields = h.fields(attrs)
+	}
+
+	ce.Write(fields...)
+
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return &handler{
+		core: h.core.With(h.fields(attrs)),
+		opts: h.opts,
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+
+	return &handler{
+		core:   h.core,
+		opts:   h.opts,
+		groups: append(groups, name),
+	}
+}
+
+// attrsMarshaler serializes the attributes of a slog group.
+type attrsMarshaler []slog.Attr
+
+func (m attrsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range m {
+		attrField(attr).AddTo(enc)
+	}
+
+	return nil
+}
+
+// attrField converts a slog.Attr into a zap field.
+func attrField(attr slog.Attr) zap.Field {
+	if v, ok := attr.Value.Any().(fieldValuer); ok {
+		f := v.Field
+		f.Key = attr.Key
+
+		// Object fields are inlined only when the attribute key is empty, as
+		// slog does with groups.
+		if m, ok := f.Interface.(zapcore.ObjectMarshaler); ok {
+			if f.Key == "" {
+				return zap.Inline(m)
+			}
+
+			return zap.Object(f.Key, m)
+		}
+
+		return f
+	}
+
+	val := attr.Value.Resolve()
+
+	switch val.Kind() {
+	case slog.KindString:
+		return zap.String(attr.Key, val.String())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, val.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, val.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, val.Float64())
+	case slog.KindBool:
+		return zap.Bool(attr.Key, val.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, val.Duration())
+	case slog.KindTime:
+		return zap.Time(attr.Key, val.Time())
+	case slog.KindGroup:
+		attrs := val.Group()
+		if len(attrs) == 0 {
+			return zap.Skip()
+		}
+
+		if attr.Key == "" {
+			return zap.Inline(attrsMarshaler(attrs))
+		}
+
+		return zap.Object(attr.Key, attrsMarshaler(attrs))
+	}
+
+	if attr.Key == "" && val.Any() == nil {
+		return zap.Skip()
+	}
+
+	if msg, ok := val.Any().(proto.Message); ok {
+		return zap.Object(attr.Key, protolog.MarshalerOf(msg))
+	}
+
+	return zap.Any(attr.Key, val.Any())
+}
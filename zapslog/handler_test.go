@@ -0,0 +1,177 @@
+package zapslog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/zapslog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHandler_Slogtest(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+
+	err := slogtest.TestHandler(zapslog.NewHandler(obs), func() []map[string]any {
+		var result []map[string]any
+
+		for _, entry := range logs.All() {
+			m := entry.ContextMap()
+			m[slog.MessageKey] = entry.Message
+			m[slog.LevelKey] = entry.Level
+
+			if !entry.Time.IsZero() {
+				m[slog.TimeKey] = entry.Time
+			}
+
+			result = append(result, m)
+		}
+
+		return result
+	})
+
+	assert.NoError(t, err, "handler should satisfy slogtest")
+}
+
+func TestHandler(t *testing.T) {
+	type Context struct {
+		Options      zapslog.Options
+		Log          func(logger *slog.Logger)
+		AssertResult func(logs *observer.ObservedLogs)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with levels": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *slog.Logger) {
+				logger.Debug("debug")
+				logger.Info("info")
+				logger.Warn("warn")
+				logger.Error("error")
+				logger.Log(context.Background(), slog.LevelError+4, "critical")
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs) {
+				var levels []zapcore.Level
+				for _, entry := range logs.All() {
+					levels = append(levels, entry.Level)
+				}
+
+				assert.Equal(t, []zapcore.Level{
+					zapcore.InfoLevel,
+					zapcore.WarnLevel,
+					zapcore.ErrorLevel,
+					zapcore.ErrorLevel,
+				}, levels, "debug level should be disabled")
+			}
+		},
+
+		"with attributes and groups": func(t *testing.T, tc *Context) {
+			tc.Log = func(logger *slog.Logger) {
+				logger.With("service", "test").WithGroup("request").WithGroup("empty").Info("message",
+					slog.Int("id", 1),
+					slog.Duration("elapsed", time.Second),
+					slog.Group("user", slog.String("name", "zapf"), slog.Bool("admin", true)),
+					slog.Group("none"),
+				)
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs) {
+				require.Equal(t, 1, logs.Len(), "message should be logged")
+				assert.Equal(t, map[string]interface{}{
+					"service": "test",
+					"request": map[string]interface{}{
+						"empty": map[string]interface{}{
+							"id":      int64(1),
+							"elapsed": time.Second,
+							"user": map[string]interface{}{
+								"name":  "zapf",
+								"admin": true,
+							},
+						},
+					},
+				}, logs.All()[0].ContextMap())
+			}
+		},
+
+		"with field values": func(t *testing.T, tc *Context) {
+			sc := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{2},
+			})
+
+			tc.Log = func(logger *slog.Logger) {
+				logger.Info("message",
+					slog.Any("", zapslog.Context(trace.ContextWithSpanContext(context.Background(), sc))),
+					slog.Any("message", zapslog.Message(&marshalerpb.Message{Text: "hello"})),
+					slog.Any("proto", &marshalerpb.Message{Text: "world"}),
+					slog.Any("trace", zapslog.Context(trace.ContextWithSpanContext(context.Background(), sc))),
+				)
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs) {
+				require.Equal(t, 1, logs.Len(), "message should be logged")
+				assert.Equal(t, map[string]interface{}{
+					"traceId": sc.TraceID().String(),
+					"spanId":  sc.SpanID().String(),
+					"message": map[string]interface{}{"text": "hello"},
+					"proto":   map[string]interface{}{"text": "world"},
+					"trace": map[string]interface{}{
+						"traceId": sc.TraceID().String(),
+						"spanId":  sc.SpanID().String(),
+					},
+				}, logs.All()[0].ContextMap())
+			}
+		},
+
+		"with logger name and source": func(t *testing.T, tc *Context) {
+			tc.Options = zapslog.Options{
+				LoggerName: "slog",
+				AddSource:  true,
+			}
+			tc.Log = func(logger *slog.Logger) {
+				logger.Info("message")
+			}
+
+			tc.AssertResult = func(logs *observer.ObservedLogs) {
+				require.Equal(t, 1, logs.Len(), "message should be logged")
+
+				entry := logs.All()[0]
+				assert.Equal(t, "slog", entry.LoggerName)
+				assert.True(t, entry.Caller.Defined, "caller should be defined")
+				assert.Contains(t, entry.Caller.File, "handler_test.go")
+				assert.Contains(t, entry.Caller.Function, "TestHandler")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			obs, logs := observer.New(zapcore.InfoLevel)
+			tc.Log(slog.New(tc.Options.NewHandler(obs)))
+
+			tc.AssertResult(logs)
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for lvl, expects := range map[slog.Level]zapcore.Level{
+		slog.LevelDebug - 4: zapcore.DebugLevel,
+		slog.LevelDebug:     zapcore.DebugLevel,
+		slog.LevelInfo:      zapcore.InfoLevel,
+		slog.LevelInfo + 2:  zapcore.InfoLevel,
+		slog.LevelWarn:      zapcore.WarnLevel,
+		slog.LevelError:     zapcore.ErrorLevel,
+		slog.LevelError + 4: zapcore.ErrorLevel,
+	} {
+		assert.Equal(t, expects, zapslog.Level(lvl), "level %s should be converted", lvl)
+	}
+}
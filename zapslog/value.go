@@ -0,0 +1,62 @@
+package zapslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/adzil/zapf/internal/protolog"
+	"github.com/adzil/zapf/zaptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+)
+
+// fieldValuer is a slog.LogValuer that converts a zap field into a slog
+// value. Handlers constructed by NewHandler use the field directly instead.
+type fieldValuer struct {
+	Field zap.Field
+}
+
+func (v fieldValuer) LogValue() slog.Value {
+	enc := newObjectEncoder()
+	v.Field.AddTo(enc)
+
+	attrs := toAttrs(enc.KeyValues())
+	if v.Field.Type != zapcore.InlineMarshalerType && len(attrs) == 1 && attrs[0].Key == v.Field.Key {
+		return attrs[0].Value
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Field constructs a slog.LogValuer from a zap field. Inline fields are
+// converted into a group value with an attribute for each inlined field, which
+// slog handlers inline when the attribute key is empty.
+func Field(f zap.Field) slog.LogValuer {
+	return fieldValuer{
+		Field: f,
+	}
+}
+
+// Message constructs a slog.LogValuer with a Protobuf message. It will
+// convert the Protobuf message into a group value lazily.
+func Message(msg proto.Message) slog.LogValuer {
+	return Field(zap.Object("", protolog.MarshalerOf(msg)))
+}
+
+// TypedMessage constructs a slog.LogValuer with a Protobuf message. It will
+// convert the Protobuf message with its type URL into a group value lazily.
+func TypedMessage(msg proto.Message) slog.LogValuer {
+	opts := protolog.Options{
+		Typed: true,
+	}
+
+	return Field(zap.Object("", opts.MarshalerOf(msg)))
+}
+
+// Context constructs a slog.LogValuer with the trace context from ctx. It
+// will convert the trace and span IDs into a group value lazily. Use it with
+// an empty attribute key to inline the IDs.
+func Context(ctx context.Context) slog.LogValuer {
+	return Field(zaptrace.Context(ctx))
+}
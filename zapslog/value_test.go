@@ -0,0 +1,106 @@
+package zapslog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/zapslog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type testObject struct{}
+
+func (testObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", "zapf")
+	enc.AddTime("time", time.Unix(1, 0).UTC())
+
+	if err := enc.AddArray("items", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt(1)
+		enc.AppendString("two")
+
+		return enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddBool("three", true)
+
+			return nil
+		}))
+	})); err != nil {
+		return err
+	}
+
+	enc.OpenNamespace("ns")
+	enc.AddUint64("count", 4)
+
+	return nil
+}
+
+func TestField(t *testing.T) {
+	type Context struct {
+		Input   slog.LogValuer
+		Expects slog.Value
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with message": func(t *testing.T, tc *Context) {
+			tc.Input = zapslog.Message(&marshalerpb.Message{Text: "hello"})
+
+			tc.Expects = slog.GroupValue(slog.String("text", "hello"))
+		},
+
+		"with typed message": func(t *testing.T, tc *Context) {
+			tc.Input = zapslog.TypedMessage(&marshalerpb.Message{Text: "hello"})
+
+			tc.Expects = slog.GroupValue(
+				slog.String("@type", "type.googleapis.com/zapf.marshaler.Message"),
+				slog.String("text", "hello"),
+			)
+		},
+
+		"with context": func(t *testing.T, tc *Context) {
+			sc := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{2},
+			})
+
+			tc.Input = zapslog.Context(trace.ContextWithSpanContext(context.Background(), sc))
+
+			tc.Expects = slog.GroupValue(
+				slog.String("traceId", sc.TraceID().String()),
+				slog.String("spanId", sc.SpanID().String()),
+			)
+		},
+
+		"with object field": func(t *testing.T, tc *Context) {
+			tc.Input = zapslog.Field(zap.Object("object", testObject{}))
+
+			tc.Expects = slog.GroupValue(
+				slog.String("name", "zapf"),
+				slog.Time("time", time.Unix(1, 0).UTC()),
+				slog.Any("items", []interface{}{
+					int64(1),
+					"two",
+					map[string]interface{}{"three": true},
+				}),
+				slog.Group("ns", slog.Uint64("count", 4)),
+			)
+		},
+
+		"with primitive field": func(t *testing.T, tc *Context) {
+			tc.Input = zapslog.Field(zap.Int("count", 1))
+
+			tc.Expects = slog.Int64Value(1)
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			assert.Equal(t, tc.Expects.String(), tc.Input.LogValue().String(), "log value should match")
+		})
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/adzil/zapf/zapstruct
+
+go 1.21
+
+require (
+	github.com/adzil/zapf v0.2.0
+	github.com/stretchr/testify v1.9.0
+	go.uber.org/zap v1.26.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,363 @@
+package zapstruct
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adzil/zapf/internal/encoder"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// RedactedValue replaces the values of fields tagged with redact.
+	RedactedValue = "[REDACTED]"
+	// CycleValue replaces the values that refer back to one of their parents.
+	CycleValue = "[CYCLE]"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	objectMarshalerType = reflect.TypeOf((*zapcore.ObjectMarshaler)(nil)).Elem()
+	arrayMarshalerType  = reflect.TypeOf((*zapcore.ArrayMarshaler)(nil)).Elem()
+	errorType           = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// visited holds the pointers of the values being serialized to detect cycles.
+type visited map[visitedKey]struct{}
+
+type visitedKey struct {
+	Ptr  uintptr
+	Type reflect.Type
+}
+
+// enter reports whether the referenced value of v is not being serialized and
+// marks it otherwise. The caller must call leave after it has been serialized.
+func (vs visited) enter(v reflect.Value) (visitedKey, bool) {
+	key := visitedKey{Ptr: v.Pointer(), Type: v.Type()}
+	if _, ok := vs[key]; ok {
+		return key, false
+	}
+
+	vs[key] = struct{}{}
+
+	return key, true
+}
+
+func appendValue[E encoder.Encoder](enc E, v reflect.Value, vs visited) error {
+	if !v.IsValid() {
+		return enc.AppendReflected(nil)
+	}
+
+	t := v.Type()
+
+	if v.CanInterface() {
+		switch {
+		case t == timeType:
+			enc.AppendTime(v.Interface().(time.Time))
+
+			return nil
+
+		case t == durationType:
+			enc.AppendDuration(time.Duration(v.Int()))
+
+			return nil
+
+		case t.Implements(objectMarshalerType):
+			if v.Kind() == reflect.Pointer && v.IsNil() {
+				return enc.AppendReflected(nil)
+			}
+
+			return enc.AppendObject(v.Interface().(zapcore.ObjectMarshaler))
+
+		case t.Implements(arrayMarshalerType):
+			if v.Kind() == reflect.Pointer && v.IsNil() {
+				return enc.AppendReflected(nil)
+			}
+
+			return enc.AppendArray(v.Interface().(zapcore.ArrayMarshaler))
+
+		case t.Implements(errorType):
+			if v.Kind() == reflect.Pointer && v.IsNil() {
+				return enc.AppendReflected(nil)
+			}
+
+			enc.AppendString(v.Interface().(error).Error())
+
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		enc.AppendBool(v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		enc.AppendInt64(v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		enc.AppendUint64(v.Uint())
+
+	case reflect.Float32:
+		enc.AppendFloat32(float32(v.Float()))
+
+	case reflect.Float64:
+		enc.AppendFloat64(v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		enc.AppendComplex128(v.Complex())
+
+	case reflect.String:
+		enc.AppendString(v.String())
+
+	case reflect.Struct:
+		return enc.AppendObject(structMarshaler{Value: v, Visited: vs})
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return enc.AppendReflected(nil)
+		}
+
+		if t.Elem().Kind() == reflect.Uint8 {
+			enc.AppendString(base64.StdEncoding.EncodeToString(v.Bytes()))
+
+			return nil
+		}
+
+		key, ok := vs.enter(v)
+		if !ok {
+			enc.AppendString(CycleValue)
+
+			return nil
+		}
+
+		defer delete(vs, key)
+
+		return enc.AppendArray(sliceMarshaler{Value: v, Visited: vs})
+
+	case reflect.Array:
+		return enc.AppendArray(sliceMarshaler{Value: v, Visited: vs})
+
+	case reflect.Map:
+		if v.IsNil() {
+			return enc.AppendReflected(nil)
+		}
+
+		key, ok := vs.enter(v)
+		if !ok {
+			enc.AppendString(CycleValue)
+
+			return nil
+		}
+
+		defer delete(vs, key)
+
+		return enc.AppendObject(mapMarshaler{Value: v, Visited: vs})
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return enc.AppendReflected(nil)
+		}
+
+		key, ok := vs.enter(v)
+		if !ok {
+			enc.AppendString(CycleValue)
+
+			return nil
+		}
+
+		defer delete(vs, key)
+
+		return appendValue(enc, v.Elem(), vs)
+
+	case reflect.Interface:
+		return appendValue(enc, v.Elem(), vs)
+
+	default:
+		return fmt.Errorf("cannot marshal value of type %s", t)
+	}
+
+	return nil
+}
+
+// isEmpty reports whether a value is omitted by the omitempty option.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+
+	return v.IsZero()
+}
+
+type structMarshaler struct {
+	Value   reflect.Value
+	Visited visited
+}
+
+func (m structMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, fp := range planOf(m.Value.Type()) {
+		fv, err := m.Value.FieldByIndexErr(fp.Index)
+		if err != nil {
+			// The field is promoted through a nil embedded pointer.
+			continue
+		}
+
+		if fp.OmitEmpty && isEmpty(fv) {
+			continue
+		}
+
+		if fp.Redact {
+			enc.AddString(fp.Name, RedactedValue)
+
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+			continue
+		}
+
+		if err := appendValue(encoder.Field(enc, fp.Name), fv, m.Visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type sliceMarshaler struct {
+	Value   reflect.Value
+	Visited visited
+}
+
+func (m sliceMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < m.Value.Len(); i++ {
+		if err := appendValue(enc, m.Value.Index(i), m.Visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type mapMarshaler struct {
+	Value   reflect.Value
+	Visited visited
+}
+
+// mapKey formats a map key like encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	switch k.Kind() {
+	case reflect.String:
+		return k.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+
+	if k.CanInterface() {
+		if s, ok := k.Interface().(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot marshal map key of type %s", k.Type())
+}
+
+func (m mapMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, m.Value.Len())
+	values := make(map[string]reflect.Value, m.Value.Len())
+
+	iter := m.Value.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+		values[key] = iter.Value()
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := appendValue(encoder.Field(enc, key), values[key], m.Visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type objectMarshalerFunc func(enc zapcore.ObjectEncoder) error
+
+func (fn objectMarshalerFunc) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return fn(enc)
+}
+
+// MarshalerOf returns a zapcore.ObjectMarshaler of a struct or a pointer to a
+// struct. Exported fields are serialized with the name, omitempty and redact
+// options of their log struct tag, such as `log:"name,omitempty,redact"`.
+// Fields tagged with `log:"-"` are skipped, and fields without log struct tag
+// are named after their json struct tag or field name. Values referring back
+// to one of their parents are serialized as CycleValue. Values other than
+// structs are serialized as an empty object.
+func MarshalerOf(v interface{}) zapcore.ObjectMarshaler {
+	return rootMarshaler{
+		Value: reflect.ValueOf(v),
+	}
+}
+
+// rootMarshaler serializes the value given to MarshalerOf. The visited set is
+// created on each call, so the marshaler can be used concurrently.
+type rootMarshaler struct {
+	Value reflect.Value
+}
+
+func (m rootMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	vs := visited{}
+
+	rv := m.Value
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		vs.enter(rv)
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return structMarshaler{
+		Value:   rv,
+		Visited: vs,
+	}.MarshalLogObject(enc)
+}
+
+// Struct constructs a field with a given key and a struct. It will serialize
+// the struct lazily. See MarshalerOf for details.
+func Struct(key string, v interface{}) zap.Field {
+	return zap.Object(key, MarshalerOf(v))
+}
+
+// Any constructs a field with a given key and an arbitrary value. Structs
+// nested anywhere in the value are serialized with the same rules as
+// MarshalerOf lazily.
+func Any(key string, v interface{}) zap.Field {
+	field := zap.Inline(objectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		return appendValue(encoder.Field(enc, key), reflect.ValueOf(v), visited{})
+	}))
+
+	// The key is only used to report marshaling errors of inline fields.
+	field.Key = key
+
+	return field
+}
@@ -0,0 +1,314 @@
+package zapstruct_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adzil/zapf/zapstruct"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+type Address struct {
+	City    string `log:"city"`
+	Country string `log:"country,omitempty"`
+}
+
+type Audit struct {
+	CreatedAt time.Time     `log:"createdAt"`
+	TTL       time.Duration `log:"ttl"`
+}
+
+type User struct {
+	Audit
+
+	ID       int64             `log:"id"`
+	Name     string            `json:"name"`
+	Password string            `log:"password,redact"`
+	Email    string            `log:"email,omitempty,redact"`
+	Internal string            `log:"-"`
+	Tags     []string          `log:"tags,omitempty"`
+	Labels   map[string]string `log:"labels"`
+	Address  *Address          `log:"address"`
+	Avatar   []byte            `log:"avatar,omitempty"`
+	Err      error             `log:"error,omitempty"`
+	Score    float64
+	Callback func()
+	secret   string
+}
+
+type Node struct {
+	Name   string `log:"name"`
+	Parent *Node  `log:"parent,omitempty"`
+	Next   *Node  `log:"next,omitempty"`
+}
+
+type List struct {
+	*List
+
+	Value int `log:"value"`
+}
+
+type Base struct {
+	ID    int64  `log:"id"`
+	Name  string `log:"name"`
+	Kind  string
+	Other string
+}
+
+type Extra struct {
+	Kind  string
+	Other string
+}
+
+type Derived struct {
+	Base
+	Extra
+
+	Name string `log:"name"`
+	Kind string `log:"kind"`
+}
+
+type marshaledID int
+
+func (id marshaledID) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("id", int(id))
+
+	return nil
+}
+
+func TestStruct(t *testing.T) {
+	type Context struct {
+		Input   interface{}
+		Expects map[string]interface{}
+	}
+
+	createdAt := time.Unix(1, 0).UTC()
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with tagged struct": func(t *testing.T, tc *Context) {
+			tc.Input = &User{
+				Audit: Audit{
+					CreatedAt: createdAt,
+					TTL:       time.Minute,
+				},
+				ID:       1,
+				Name:     "zapf",
+				Password: "secret",
+				Internal: "internal",
+				Labels:   map[string]string{"b": "2", "a": "1"},
+				Address:  &Address{City: "Jakarta"},
+				Avatar:   []byte("avatar"),
+				Err:      errors.New("test error"),
+				Score:    1.5,
+				Callback: func() {},
+				secret:   "secret",
+			}
+
+			tc.Expects = map[string]interface{}{
+				"createdAt": createdAt,
+				"ttl":       time.Minute,
+				"id":        int64(1),
+				"name":      "zapf",
+				"password":  zapstruct.RedactedValue,
+				"labels":    map[string]interface{}{"a": "1", "b": "2"},
+				"address":   map[string]interface{}{"city": "Jakarta"},
+				"avatar":    "YXZhdGFy",
+				"error":     "test error",
+				"Score":     1.5,
+			}
+		},
+
+		"with nil fields": func(t *testing.T, tc *Context) {
+			tc.Input = User{}
+
+			tc.Expects = map[string]interface{}{
+				"createdAt": time.Time{},
+				"ttl":       time.Duration(0),
+				"id":        int64(0),
+				"name":      "",
+				"password":  zapstruct.RedactedValue,
+				"labels":    nil,
+				"address":   nil,
+				"Score":     float64(0),
+			}
+		},
+
+		"with cycle": func(t *testing.T, tc *Context) {
+			root := &Node{Name: "root"}
+			child := &Node{Name: "child", Parent: root}
+			root.Next = child
+
+			tc.Input = root
+
+			tc.Expects = map[string]interface{}{
+				"name": "root",
+				"next": map[string]interface{}{
+					"name":   "child",
+					"parent": zapstruct.CycleValue,
+				},
+			}
+		},
+
+		"with repeated non-cyclic pointer": func(t *testing.T, tc *Context) {
+			shared := &Node{Name: "shared"}
+
+			tc.Input = struct {
+				A *Node `log:"a"`
+				B *Node `log:"b"`
+			}{shared, shared}
+
+			tc.Expects = map[string]interface{}{
+				"a": map[string]interface{}{"name": "shared"},
+				"b": map[string]interface{}{"name": "shared"},
+			}
+		},
+
+		"with recursive embedded pointer": func(t *testing.T, tc *Context) {
+			tc.Input = List{List: &List{Value: 2}, Value: 1}
+
+			tc.Expects = map[string]interface{}{
+				"value": int64(1),
+			}
+		},
+
+		"with conflicting embedded fields": func(t *testing.T, tc *Context) {
+			tc.Input = Derived{
+				Base:  Base{ID: 1, Name: "base", Kind: "base", Other: "base"},
+				Extra: Extra{Kind: "extra", Other: "extra"},
+				Name:  "derived",
+				Kind:  "derived",
+			}
+
+			tc.Expects = map[string]interface{}{
+				"id":   int64(1),
+				"name": "derived",
+				"kind": "derived",
+			}
+		},
+
+		"with object marshaler": func(t *testing.T, tc *Context) {
+			tc.Input = struct {
+				ID   marshaledID   `log:"id"`
+				IDs  []marshaledID `log:"ids"`
+				Any  interface{}   `log:"any"`
+				Nums [2]uint8      `log:"nums"`
+			}{1, []marshaledID{2}, Address{City: "Bandung"}, [2]uint8{3, 4}}
+
+			tc.Expects = map[string]interface{}{
+				"id":   map[string]interface{}{"id": 1},
+				"ids":  []interface{}{map[string]interface{}{"id": 2}},
+				"any":  map[string]interface{}{"city": "Bandung"},
+				"nums": []interface{}{uint64(3), uint64(4)},
+			}
+		},
+
+		"with non-struct value": func(t *testing.T, tc *Context) {
+			tc.Input = "value"
+
+			tc.Expects = map[string]interface{}{}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			field := zapstruct.Struct("struct", tc.Input)
+			assert.Equal(t, zapcore.ObjectMarshalerType, field.Type)
+
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+
+			assert.Equal(t, tc.Expects, enc.Fields["struct"], "encoded struct should match")
+		})
+	}
+}
+
+func TestStruct_Concurrent(t *testing.T) {
+	root := &Node{Name: "root"}
+	root.Next = &Node{Name: "child", Parent: root}
+
+	field := zapstruct.Struct("struct", root)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+
+			assert.Equal(t, map[string]interface{}{
+				"name": "root",
+				"next": map[string]interface{}{
+					"name":   "child",
+					"parent": zapstruct.CycleValue,
+				},
+			}, enc.Fields["struct"], "encoded struct should match")
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestAny(t *testing.T) {
+	type Context struct {
+		Input   interface{}
+		Expects interface{}
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with slice of structs": func(t *testing.T, tc *Context) {
+			tc.Input = []Address{{City: "Jakarta", Country: "ID"}}
+
+			tc.Expects = []interface{}{
+				map[string]interface{}{"city": "Jakarta", "country": "ID"},
+			}
+		},
+
+		"with map": func(t *testing.T, tc *Context) {
+			tc.Input = map[int]*Address{2: {City: "Bandung"}, 1: nil}
+
+			tc.Expects = map[string]interface{}{
+				"1": nil,
+				"2": map[string]interface{}{"city": "Bandung"},
+			}
+		},
+
+		"with primitive": func(t *testing.T, tc *Context) {
+			tc.Input = uint16(7)
+
+			tc.Expects = uint64(7)
+		},
+
+		"with nil": func(t *testing.T, tc *Context) {
+			tc.Input = nil
+
+			tc.Expects = nil
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			enc := zapcore.NewMapObjectEncoder()
+			zapstruct.Any("value", tc.Input).AddTo(enc)
+
+			assert.Equal(t, tc.Expects, enc.Fields["value"], "encoded value should match")
+		})
+	}
+}
+
+func TestAny_Unsupported(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	zapstruct.Any("value", make(chan int)).AddTo(enc)
+
+	assert.Equal(t, map[string]interface{}{
+		"valueError": "cannot marshal value of type chan int",
+	}, enc.Fields, "unsupported value should be reported")
+}
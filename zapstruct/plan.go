@@ -0,0 +1,195 @@
+package zapstruct
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const tagName = "log"
+
+// fieldPlan describes how a struct field is serialized.
+type fieldPlan struct {
+	Name      string
+	Index     []int
+	Tagged    bool
+	OmitEmpty bool
+	Redact    bool
+}
+
+// plans caches the field plans of each struct type.
+var plans sync.Map
+
+// planOf returns the cached field plans of a struct type.
+func planOf(t reflect.Type) []fieldPlan {
+	if v, ok := plans.Load(t); ok {
+		return v.([]fieldPlan)
+	}
+
+	v, _ := plans.LoadOrStore(t, buildPlan(t))
+
+	return v.([]fieldPlan)
+}
+
+// parseTag parses the log struct tag of a field. The json struct tag name is
+// used when the field has no log struct tag.
+func parseTag(sf reflect.StructField) (name string, omitEmpty, redact, ok bool) {
+	tag, hasTag := sf.Tag.Lookup(tagName)
+	if !hasTag {
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			return "", false, false, false
+		}
+
+		return jsonName, false, false, true
+	}
+
+	if tag == "-" {
+		return "", false, false, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+
+		switch opt {
+		case "omitempty":
+			omitEmpty = true
+		case "redact":
+			redact = true
+		}
+	}
+
+	return name, omitEmpty, redact, true
+}
+
+// buildPlan returns the field plans of a struct type. Untagged embedded
+// structs are flattened level by level like encoding/json, where each
+// embedded type is only expanded once to stop at recursive embedding, and
+// fields with the same name are resolved by the encoding/json dominance rules.
+func buildPlan(t reflect.Type) []fieldPlan {
+	type embedded struct {
+		Type  reflect.Type
+		Index []int
+	}
+
+	var fields []fieldPlan
+
+	next := []embedded{{Type: t}}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		// count records how many times each type is embedded in this level, as
+		// the fields of a type embedded more than once annihilate each other.
+		count := map[reflect.Type]int{}
+		for _, e := range current {
+			count[e.Type]++
+		}
+
+		for _, e := range current {
+			if visited[e.Type] {
+				continue
+			}
+			visited[e.Type] = true
+
+			for i := 0; i < e.Type.NumField(); i++ {
+				sf := e.Type.Field(i)
+
+				name, omitEmpty, redact, ok := parseTag(sf)
+				if !ok {
+					continue
+				}
+
+				index := make([]int, len(e.Index), len(e.Index)+1)
+				copy(index, e.Index)
+				index = append(index, i)
+
+				// Untagged embedded structs are flattened in the next level.
+				if sf.Anonymous && name == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{Type: ft, Index: index})
+
+						continue
+					}
+				}
+
+				if !sf.IsExported() {
+					continue
+				}
+
+				fp := fieldPlan{
+					Name:      name,
+					Index:     index,
+					Tagged:    name != "",
+					OmitEmpty: omitEmpty,
+					Redact:    redact,
+				}
+				if fp.Name == "" {
+					fp.Name = sf.Name
+				}
+
+				fields = append(fields, fp)
+				if count[e.Type] > 1 {
+					fields = append(fields, fp)
+				}
+			}
+		}
+	}
+
+	return dominantFields(fields)
+}
+
+// dominantFields keeps the dominant field of each name, which is the shallowest
+// field preferring the tagged one. Names without a single dominant field are
+// dropped. The fields are returned in their struct order.
+func dominantFields(fields []fieldPlan) []fieldPlan {
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if len(a.Index) != len(b.Index) {
+			return len(a.Index) < len(b.Index)
+		}
+
+		return a.Tagged && !b.Tagged
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].Name == fields[i].Name {
+			j++
+		}
+
+		if j-i == 1 || len(fields[i].Index) < len(fields[i+1].Index) ||
+			fields[i].Tagged != fields[i+1].Tagged {
+			dominant = append(dominant, fields[i])
+		}
+
+		i = j
+	}
+
+	sort.Slice(dominant, func(i, j int) bool {
+		a, b := dominant[i].Index, dominant[j].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+
+		return len(a) < len(b)
+	})
+
+	return dominant
+}
@@ -0,0 +1,180 @@
+package zaptrace
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/adzil/zapf/internal/fieldenc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// attributeValue is an encoded field value. Nested objects and arrays have an
+// invalid Value and are kept as trees to be flattened into dotted attribute
+// keys.
+type attributeValue struct {
+	Value  attribute.Value
+	Object []fieldenc.KeyValue[attributeValue]
+	Array  []attributeValue
+}
+
+// attributeConverter converts the encoded fields into attributeValue.
+type attributeConverter struct{}
+
+func (attributeConverter) Object(kvs []fieldenc.KeyValue[attributeValue]) attributeValue {
+	return attributeValue{Object: kvs}
+}
+
+func (attributeConverter) Array(vs []attributeValue) attributeValue {
+	return attributeValue{Array: vs}
+}
+
+func (attributeConverter) Binary(b []byte) attributeValue {
+	return attributeValue{Value: attribute.StringValue(base64.StdEncoding.EncodeToString(b))}
+}
+
+func (attributeConverter) Bool(b bool) attributeValue {
+	return attributeValue{Value: attribute.BoolValue(b)}
+}
+
+func (attributeConverter) Duration(d time.Duration) attributeValue {
+	return attributeValue{Value: attribute.StringValue(d.String())}
+}
+
+func (attributeConverter) Float64(f float64) attributeValue {
+	return attributeValue{Value: attribute.Float64Value(f)}
+}
+
+func (attributeConverter) Int64(i int64) attributeValue {
+	return attributeValue{Value: attribute.Int64Value(i)}
+}
+
+func (attributeConverter) String(s string) attributeValue {
+	return attributeValue{Value: attribute.StringValue(s)}
+}
+
+func (attributeConverter) Time(t time.Time) attributeValue {
+	return attributeValue{Value: attribute.StringValue(t.Format(time.RFC3339Nano))}
+}
+
+func (attributeConverter) Uint64(u uint64) attributeValue {
+	if u > math.MaxInt64 {
+		return attributeValue{Value: attribute.StringValue(strconv.FormatUint(u, 10))}
+	}
+
+	return attributeValue{Value: attribute.Int64Value(int64(u))}
+}
+
+func (attributeConverter) Reflected(v interface{}) (attributeValue, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return attributeValue{}, err
+	}
+
+	return attributeValue{Value: attribute.StringValue(string(b))}, nil
+}
+
+// sliceAttribute constructs a slice attribute from primitive values. Values
+// of mixed types are converted into a string slice.
+func sliceAttribute(key string, values []attribute.Value) (attribute.KeyValue, bool) {
+	if len(values) == 0 {
+		return attribute.KeyValue{}, false
+	}
+
+	typ := values[0].Type()
+	for _, v := range values[1:] {
+		if v.Type() != typ {
+			typ = attribute.INVALID
+
+			break
+		}
+	}
+
+	switch typ {
+	case attribute.BOOL:
+		vs := make([]bool, len(values))
+		for i, v := range values {
+			vs[i] = v.AsBool()
+		}
+
+		return attribute.BoolSlice(key, vs), true
+
+	case attribute.INT64:
+		vs := make([]int64, len(values))
+		for i, v := range values {
+			vs[i] = v.AsInt64()
+		}
+
+		return attribute.Int64Slice(key, vs), true
+
+	case attribute.FLOAT64:
+		vs := make([]float64, len(values))
+		for i, v := range values {
+			vs[i] = v.AsFloat64()
+		}
+
+		return attribute.Float64Slice(key, vs), true
+	}
+
+	vs := make([]string, len(values))
+	for i, v := range values {
+		vs[i] = v.Emit()
+	}
+
+	return attribute.StringSlice(key, vs), true
+}
+
+// appendAttributes flattens the fields into attributes. Nested objects are
+// flattened into dotted attribute keys. The primitive elements of arrays are
+// collected into a single slice attribute, while their nested objects and
+// arrays are flattened into dotted attribute keys with their index.
+func appendAttributes(attrs []attribute.KeyValue, prefix string, kvs []fieldenc.KeyValue[attributeValue]) []attribute.KeyValue {
+	for _, kv := range kvs {
+		key := prefix + kv.Key
+
+		if kv.Value.Value.Type() != attribute.INVALID {
+			attrs = append(attrs, attribute.KeyValue{
+				Key:   attribute.Key(key),
+				Value: kv.Value.Value,
+			})
+
+			continue
+		}
+
+		attrs = appendAttributes(attrs, key+".", kv.Value.Object)
+
+		var values []attribute.Value
+
+		for i, v := range kv.Value.Array {
+			if v.Value.Type() != attribute.INVALID {
+				values = append(values, v.Value)
+
+				continue
+			}
+
+			attrs = appendAttributes(attrs, key+".", []fieldenc.KeyValue[attributeValue]{{
+				Key:   strconv.Itoa(i),
+				Value: v,
+			}})
+		}
+
+		if attr, ok := sliceAttribute(key, values); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+// fieldsToAttributes converts zap fields into attribute.KeyValue.
+func fieldsToAttributes(fields []zapcore.Field) []attribute.KeyValue {
+	enc := fieldenc.NewObjectEncoder[attributeValue](attributeConverter{})
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	return appendAttributes(make([]attribute.KeyValue, 0, len(fields)), "", enc.KeyValues())
+}
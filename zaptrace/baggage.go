@@ -0,0 +1,119 @@
+package zaptrace
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// BaggageOptions configures the field constructed by BaggageOptions.Baggage.
+type BaggageOptions struct {
+	// Allow lists the member keys to be logged. All members are logged when
+	// it is empty.
+	Allow []string
+	// Deny lists the member keys that are never logged. It takes precedence
+	// over Allow.
+	Deny []string
+	// Properties logs each member as an object containing its value and
+	// properties instead of a plain string.
+	Properties bool
+}
+
+func (opts BaggageOptions) included(key string) bool {
+	for _, k := range opts.Deny {
+		if k == key {
+			return false
+		}
+	}
+
+	if len(opts.Allow) == 0 {
+		return true
+	}
+
+	for _, k := range opts.Allow {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+type baggageMemberMarshaler struct {
+	Member baggage.Member
+}
+
+func (m baggageMemberMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("value", m.Member.Value())
+
+	props := m.Member.Properties()
+	if len(props) == 0 {
+		return nil
+	}
+
+	return enc.AddObject("properties", baggagePropertiesMarshaler(props))
+}
+
+type baggagePropertiesMarshaler []baggage.Property
+
+func (m baggagePropertiesMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, p := range m {
+		if v, ok := p.Value(); ok {
+			enc.AddString(p.Key(), v)
+		} else {
+			enc.AddBool(p.Key(), true)
+		}
+	}
+
+	return nil
+}
+
+type baggageMarshaler struct {
+	Options BaggageOptions
+	Baggage baggage.Baggage
+}
+
+func (m baggageMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	members := m.Baggage.Members()
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Key() < members[j].Key()
+	})
+
+	for _, member := range members {
+		if !m.Options.included(member.Key()) {
+			continue
+		}
+
+		if !m.Options.Properties {
+			enc.AddString(member.Key(), member.Value())
+
+			continue
+		}
+
+		if err := enc.AddObject(member.Key(), baggageMemberMarshaler{
+			Member: member,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Baggage constructs fields from the baggage.Baggage members present in the
+// context.Context.
+func (opts BaggageOptions) Baggage(ctx context.Context) zap.Field {
+	return zap.Inline(baggageMarshaler{
+		Options: opts,
+		Baggage: baggage.FromContext(ctx),
+	})
+}
+
+// Baggage constructs fields from all baggage.Baggage members present in the
+// context.Context. Each member is logged with its key and string value.
+func Baggage(ctx context.Context) zap.Field {
+	return BaggageOptions{}.Baggage(ctx)
+}
@@ -0,0 +1,92 @@
+package zaptrace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adzil/zapf/zaprec"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBaggage(t *testing.T) {
+	type Context struct {
+		Field   func(ctx context.Context) zap.Field
+		Expects zaprec.Object
+	}
+
+	bag, err := baggage.Parse("tenant=acme;region=eu;primary,experiment=blue,secret=token")
+	require.NoError(t, err, "baggage parse should return nil error")
+
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with no baggage": func(t *testing.T, tc *Context) {
+			tc.Field = func(context.Context) zap.Field {
+				return zaptrace.Baggage(context.Background())
+			}
+		},
+
+		"with all members": func(t *testing.T, tc *Context) {
+			tc.Field = zaptrace.Baggage
+
+			tc.Expects = zaprec.Object{
+				"tenant":     zaprec.String("acme"),
+				"experiment": zaprec.String("blue"),
+				"secret":     zaprec.String("token"),
+			}
+		},
+
+		"with allow and deny list": func(t *testing.T, tc *Context) {
+			tc.Field = zaptrace.BaggageOptions{
+				Allow: []string{"tenant", "secret"},
+				Deny:  []string{"secret"},
+			}.Baggage
+
+			tc.Expects = zaprec.Object{
+				"tenant": zaprec.String("acme"),
+			}
+		},
+
+		"with member properties": func(t *testing.T, tc *Context) {
+			tc.Field = zaptrace.BaggageOptions{
+				Deny:       []string{"secret"},
+				Properties: true,
+			}.Baggage
+
+			tc.Expects = zaprec.Object{
+				"tenant": zaprec.Object{
+					"value": zaprec.String("acme"),
+					"properties": zaprec.Object{
+						"region":  zaprec.String("eu"),
+						"primary": zaprec.Bool(true),
+					},
+				},
+				"experiment": zaprec.Object{
+					"value": zaprec.String("blue"),
+				},
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			field := tc.Field(ctx)
+			assert.Equal(t, zapcore.InlineMarshalerType, field.Type)
+
+			om, ok := field.Interface.(zapcore.ObjectMarshaler)
+			require.True(t, ok, "field interface must be an object marshaler")
+
+			enc := zaprec.NewObjectEncoder()
+			err := om.MarshalLogObject(enc)
+
+			assert.NoError(t, err, "marshal log object should return nil error")
+			assert.Equal(t, tc.Expects, enc.Result(), "encoded baggage should match")
+		})
+	}
+}
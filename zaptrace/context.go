@@ -3,6 +3,7 @@ package zaptrace
 import (
 	"context"
 
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,13 +12,19 @@ import (
 // Context constructs traceId and spanId field from context.Context if a
 // trace.SpanContext is present in the context value.
 func Context(ctx context.Context) zap.Field {
-	spanCtx := trace.SpanContextFromContext(ctx)
+	span := trace.SpanFromContext(ctx)
 
-	return SpanContext(spanCtx)
+	return zap.Inline(spanContextMarshaler{
+		SpanContext: span.SpanContext(),
+		Span:        span,
+	})
 }
 
 type spanContextMarshaler struct {
 	SpanContext trace.SpanContext
+	// Span is only set when the field is constructed from context.Context or
+	// trace.Span.
+	Span trace.Span
 }
 
 func (m spanContextMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
@@ -39,3 +46,54 @@ func SpanContext(spanCtx trace.SpanContext) zap.Field {
 		SpanContext: spanCtx,
 	})
 }
+
+// spanContextMarshalerOf returns the spanContextMarshaler of a field
+// constructed from Context, SpanContext or Span.
+func spanContextMarshalerOf(f zap.Field) (spanContextMarshaler, bool) {
+	if f.Type != zapcore.InlineMarshalerType {
+		return spanContextMarshaler{}, false
+	}
+
+	switch m := f.Interface.(type) {
+	case spanContextMarshaler:
+		return m, true
+
+	case spanMarshaler:
+		return m.spanContextMarshaler, true
+	}
+
+	return spanContextMarshaler{}, false
+}
+
+// SpanContextFromField returns the trace.SpanContext carried by a field
+// constructed from Context, SpanContext or Span.
+func SpanContextFromField(f zap.Field) (trace.SpanContext, bool) {
+	m, ok := spanContextMarshalerOf(f)
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+
+	return m.SpanContext, true
+}
+
+// SplitField returns the trace.SpanContext carried by a field constructed from
+// Context, SpanContext or Span, and the rest of the field without the traceId
+// and spanId. The rest only contains the span field of Span and is zap.Skip
+// for the other fields.
+func SplitField(f zap.Field) (trace.SpanContext, zap.Field, bool) {
+	m, ok := spanContextMarshalerOf(f)
+	if !ok {
+		return trace.SpanContext{}, f, false
+	}
+
+	sm, ok := f.Interface.(spanMarshaler)
+	if !ok {
+		return m.SpanContext, zap.Skip(), true
+	}
+
+	if _, ok := sm.Span.(sdktrace.ReadOnlySpan); !ok {
+		return m.SpanContext, zap.Skip(), true
+	}
+
+	return m.SpanContext, zap.Inline(spanDetailsMarshaler(sm)), true
+}
@@ -5,26 +5,29 @@ import (
 	"crypto/rand"
 	"testing"
 
-	rec "github.com/adzil/zapf/internal/fieldrecorder"
+	"github.com/adzil/zapf"
+	"github.com/adzil/zapf/zaprec"
 	"github.com/adzil/zapf/zaptrace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestTrace(t *testing.T) {
 	type Context struct {
 		Field        zap.Field
-		AssertResult func(result rec.Object)
+		AssertResult func(result zaprec.Object)
 	}
 
 	for k, v := range map[string]func(t *testing.T, tc *Context){
 		"with no span context": func(t *testing.T, tc *Context) {
 			tc.Field = zaptrace.Context(context.Background())
 
-			tc.AssertResult = func(result rec.Object) {
+			tc.AssertResult = func(result zaprec.Object) {
 				assert.Len(t, result, 0, "encoded result should be empty")
 			}
 		},
@@ -43,12 +46,12 @@ func TestTrace(t *testing.T) {
 
 			tc.Field = zaptrace.Context(ctx)
 
-			expected := rec.Object{
-				"traceId": rec.String(config.TraceID.String()),
-				"spanId":  rec.String(config.SpanID.String()),
+			expected := zaprec.Object{
+				"traceId": zaprec.String(config.TraceID.String()),
+				"spanId":  zaprec.String(config.SpanID.String()),
 			}
 
-			tc.AssertResult = func(result rec.Object) {
+			tc.AssertResult = func(result zaprec.Object) {
 				assert.Equal(t, expected, result, "trace and span id should match")
 			}
 		},
@@ -62,7 +65,7 @@ func TestTrace(t *testing.T) {
 			om, ok := tc.Field.Interface.(zapcore.ObjectMarshaler)
 			require.True(t, ok, "field interface must be an object marshaler")
 
-			enc := rec.NewObjectEncoder(t)
+			enc := zaprec.NewObjectEncoder()
 			err := om.MarshalLogObject(enc)
 
 			assert.NoError(t, err, "marshal log object should return nil error")
@@ -70,3 +73,40 @@ func TestTrace(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanContextFromField(t *testing.T) {
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+
+	result, ok := zaptrace.SpanContextFromField(zaptrace.SpanContext(spanCtx))
+	assert.True(t, ok, "span context field should be recognized")
+	assert.Equal(t, spanCtx, result, "span context should match")
+
+	_, ok = zaptrace.SpanContextFromField(zap.String("traceId", spanCtx.TraceID().String()))
+	assert.False(t, ok, "other field should not be recognized")
+}
+
+func TestContext_Logger(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	ctx := zapf.WithFields(parentCtx, zaptrace.Span(parent), zap.String("key", "value"))
+
+	ctx, child := tp.Tracer("test").Start(ctx, "child")
+
+	obs, logs := observer.New(zapcore.InfoLevel)
+	zapf.Options{
+		Context: []func(context.Context) zap.Field{zaptrace.Context},
+	}.Logger(ctx, zap.New(obs)).Info("hello")
+
+	require.Equal(t, 1, logs.Len(), "entry should be written")
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, child.SpanContext().TraceID().String(), fields["traceId"], "trace id should be replaced")
+	assert.Equal(t, child.SpanContext().SpanID().String(), fields["spanId"], "span id should be replaced")
+	assert.Contains(t, fields, "span", "span details of the attached span should be kept")
+	assert.Equal(t, "value", fields["key"], "attached field should be kept")
+	assert.Len(t, fields, 4, "trace fields should not be duplicated")
+}
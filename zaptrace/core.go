@@ -0,0 +1,95 @@
+package zaptrace
+
+import (
+	"github.com/adzil/zapf/internal/wrapcore"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+type eventCore struct {
+	zapcore.Core
+	span   trace.Span
+	fields []zapcore.Field
+}
+
+// NewEventCore wraps a zapcore.Core to also record each written entry as an
+// event of the span carried by a field constructed from Context or Span. The
+// entry message is used as the event name and the remaining fields are
+// converted into event attributes. The span status is set to error for entries
+// with zapcore.ErrorLevel and above.
+func NewEventCore(core zapcore.Core) zapcore.Core {
+	return &eventCore{
+		Core: core,
+	}
+}
+
+// spanFromFields returns the last span carried by the fields and the fields
+// without the trace context.
+func spanFromFields(fields []zapcore.Field) (trace.Span, []zapcore.Field) {
+	var span trace.Span
+
+	filtered := make([]zapcore.Field, 0, len(fields))
+
+	for _, f := range fields {
+		m, ok := spanContextMarshalerOf(f)
+		if !ok {
+			filtered = append(filtered, f)
+
+			continue
+		}
+
+		if m.Span != nil {
+			span = m.Span
+		}
+	}
+
+	return span, filtered
+}
+
+func (c *eventCore) With(fields []zapcore.Field) zapcore.Core {
+	span, filtered := spanFromFields(fields)
+	if span == nil {
+		span = c.span
+	}
+
+	return &eventCore{
+		Core:   c.Core.With(fields),
+		span:   span,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], filtered...),
+	}
+}
+
+func (c *eventCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return wrapcore.Check(c, c.Core, ent, ce)
+}
+
+func (c *eventCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(c.Rewrite(ent, fields))
+}
+
+// Rewrite records the entry as a span event and returns it as is.
+func (c *eventCore) Rewrite(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	span, filtered := spanFromFields(fields)
+	if span == nil {
+		span = c.span
+	}
+
+	if span == nil || !span.IsRecording() {
+		return ent, fields
+	}
+
+	attrs := fieldsToAttributes(c.fields)
+	attrs = append(attrs, fieldsToAttributes(filtered)...)
+
+	span.AddEvent(ent.Message,
+		trace.WithTimestamp(ent.Time),
+		trace.WithAttributes(attrs...),
+	)
+
+	if ent.Level >= zapcore.ErrorLevel {
+		span.SetStatus(codes.Error, ent.Message)
+	}
+
+	return ent, fields
+}
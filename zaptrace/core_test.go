@@ -0,0 +1,196 @@
+package zaptrace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	marshalerpb "github.com/adzil/zapf/internal/gen/go/marshaler"
+	"github.com/adzil/zapf/internal/protolog"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestEventCore(t *testing.T) {
+	type Context struct {
+		Log          func(ctx context.Context, logger *zap.Logger)
+		AssertEvents func(span sdktrace.ReadOnlySpan)
+	}
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with fields at call site": func(t *testing.T, tc *Context) {
+			tc.Log = func(ctx context.Context, logger *zap.Logger) {
+				logger.Info("hello",
+					zaptrace.Context(ctx),
+					zap.String("string", "world"),
+					zap.Int("int", 1),
+					zap.Strings("strings", []string{"a", "b"}),
+					zap.Object("message", protolog.MarshalerOf(&marshalerpb.Message{
+						Text: "hello",
+					})),
+				)
+			}
+
+			tc.AssertEvents = func(span sdktrace.ReadOnlySpan) {
+				require.Len(t, span.Events(), 1, "span should have one event")
+
+				event := span.Events()[0]
+				assert.Equal(t, "hello", event.Name, "event name should match the message")
+				assert.Equal(t, []attribute.KeyValue{
+					attribute.String("string", "world"),
+					attribute.Int64("int", 1),
+					attribute.StringSlice("strings", []string{"a", "b"}),
+					attribute.String("message.text", "hello"),
+				}, event.Attributes, "event attributes should match the fields")
+				assert.Equal(t, codes.Unset, span.Status().Code, "span status should be unset")
+			}
+		},
+
+		"with fields from logger with": func(t *testing.T, tc *Context) {
+			tc.Log = func(ctx context.Context, logger *zap.Logger) {
+				logger.With(zaptrace.Context(ctx), zap.String("with", "value")).
+					Error("failed", zap.Error(errors.New("test error")))
+			}
+
+			tc.AssertEvents = func(span sdktrace.ReadOnlySpan) {
+				require.Len(t, span.Events(), 1, "span should have one event")
+
+				event := span.Events()[0]
+				assert.Equal(t, "failed", event.Name, "event name should match the message")
+				assert.Equal(t, []attribute.KeyValue{
+					attribute.String("with", "value"),
+					attribute.String("error", "test error"),
+				}, event.Attributes, "event attributes should match the fields")
+				assert.Equal(t, codes.Error, span.Status().Code, "span status should be error")
+				assert.Equal(t, "failed", span.Status().Description, "span status should use the message")
+			}
+		},
+
+		"with nested arrays and namespace": func(t *testing.T, tc *Context) {
+			tc.Log = func(ctx context.Context, logger *zap.Logger) {
+				logger.Info("nested",
+					zaptrace.Context(ctx),
+					zap.Array("list", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+						enc.AppendInt(1)
+						if err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+							enc.AddString("key", "value")
+
+							return nil
+						})); err != nil {
+							return err
+						}
+						enc.AppendString("a")
+
+						return nil
+					})),
+					zap.Namespace("ns"),
+					zap.Bool("bool", true),
+				)
+			}
+
+			tc.AssertEvents = func(span sdktrace.ReadOnlySpan) {
+				require.Len(t, span.Events(), 1, "span should have one event")
+
+				assert.Equal(t, []attribute.KeyValue{
+					attribute.String("list.1.key", "value"),
+					attribute.StringSlice("list", []string{"1", "a"}),
+					attribute.Bool("ns.bool", true),
+				}, span.Events()[0].Attributes, "event attributes should be flattened")
+			}
+		},
+
+		"without trace context field": func(t *testing.T, tc *Context) {
+			tc.Log = func(_ context.Context, logger *zap.Logger) {
+				logger.Error("hello")
+			}
+
+			tc.AssertEvents = func(span sdktrace.ReadOnlySpan) {
+				assert.Len(t, span.Events(), 0, "span should have no event")
+				assert.Equal(t, codes.Unset, span.Status().Code, "span status should be unset")
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+			ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+
+			obs, logs := observer.New(zapcore.DebugLevel)
+			tc.Log(ctx, zap.New(zaptrace.NewEventCore(obs)))
+
+			span.End()
+
+			assert.Equal(t, 1, logs.Len(), "entry should be written to the wrapped core")
+			require.Len(t, sr.Ended(), 1, "there should be one ended span")
+			tc.AssertEvents(sr.Ended()[0])
+		})
+	}
+}
+
+func TestEventCore_NonRecordingSpan(t *testing.T) {
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	obs, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(zaptrace.NewEventCore(obs))
+
+	logger.Debug("disabled", zaptrace.Context(ctx))
+	logger.Info("hello", zaptrace.Context(ctx))
+
+	require.Equal(t, 1, logs.Len(), "only enabled entry should be written")
+	assert.Equal(t, map[string]interface{}{
+		"traceId": spanCtx.TraceID().String(),
+		"spanId":  spanCtx.SpanID().String(),
+	}, logs.All()[0].ContextMap(), "trace context should still be written")
+}
+
+func TestEventCore_Sampler(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+
+	obs, logs := observer.New(zapcore.DebugLevel)
+	sampler := zapcore.NewSamplerWithOptions(obs, time.Minute, 1, 0)
+	logger := zap.New(zaptrace.NewEventCore(sampler))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("hello", zaptrace.Context(ctx))
+	}
+
+	span.End()
+
+	assert.Equal(t, 1, logs.Len(), "sampled entries should be dropped")
+	require.Len(t, sr.Ended(), 1, "there should be one ended span")
+	assert.Len(t, sr.Ended()[0].Events(), 1, "sampled entries should not be recorded as events")
+}
+
+func TestEventCore_Tee(t *testing.T) {
+	infoObs, infoLogs := observer.New(zapcore.InfoLevel)
+	errorObs, errorLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(zaptrace.NewEventCore(zapcore.NewTee(infoObs, errorObs)))
+
+	logger.Info("hello")
+	logger.Error("failed")
+
+	assert.Equal(t, 2, infoLogs.Len(), "info core should write both entries")
+	require.Equal(t, 1, errorLogs.Len(), "error core should only write the error entry")
+	assert.Equal(t, "failed", errorLogs.All()[0].Message, "error entry should be written")
+}
@@ -0,0 +1,72 @@
+package zaptrace
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type linkMarshaler struct {
+	SpanContext trace.SpanContext
+	Attributes  []attribute.KeyValue
+}
+
+func (m linkMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := (spanContextMarshaler{SpanContext: m.SpanContext}).MarshalLogObject(enc); err != nil {
+		return err
+	}
+
+	if m.SpanContext.IsRemote() {
+		enc.AddBool("remote", true)
+	}
+
+	if len(m.Attributes) == 0 {
+		return nil
+	}
+
+	return enc.AddObject("attributes", attributesMarshaler(m.Attributes))
+}
+
+type linksMarshaler []linkMarshaler
+
+func (m linksMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, link := range m {
+		if err := enc.AppendObject(link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func linksMarshalerOf(links []trace.Link) linksMarshaler {
+	m := make(linksMarshaler, len(links))
+	for i, link := range links {
+		m[i] = linkMarshaler{
+			SpanContext: link.SpanContext,
+			Attributes:  link.Attributes,
+		}
+	}
+
+	return m
+}
+
+func sdkLinksMarshalerOf(links []sdktrace.Link) linksMarshaler {
+	m := make(linksMarshaler, len(links))
+	for i, link := range links {
+		m[i] = linkMarshaler{
+			SpanContext: link.SpanContext,
+			Attributes:  link.Attributes,
+		}
+	}
+
+	return m
+}
+
+// Links constructs a field with a given key and trace.Link array. Each link
+// is serialized with its traceId, spanId and attributes lazily.
+func Links(key string, links []trace.Link) zap.Field {
+	return zap.Array(key, linksMarshalerOf(links))
+}
@@ -0,0 +1,66 @@
+package zaptrace_test
+
+import (
+	"testing"
+
+	"github.com/adzil/zapf/zaprec"
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLinks(t *testing.T) {
+	first := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+		Remote:  true,
+	})
+	second := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{3},
+		SpanID:  trace.SpanID{4},
+	})
+
+	field := zaptrace.Links("links", []trace.Link{
+		{
+			SpanContext: first,
+			Attributes: []attribute.KeyValue{
+				attribute.String("messaging.message.id", "1"),
+				attribute.Bool("retried", true),
+			},
+		},
+		{
+			SpanContext: second,
+		},
+	})
+
+	expected := zaprec.Array{
+		zaprec.Object{
+			"traceId": zaprec.String(first.TraceID().String()),
+			"spanId":  zaprec.String(first.SpanID().String()),
+			"remote":  zaprec.Bool(true),
+			"attributes": zaprec.Object{
+				"messaging.message.id": zaprec.String("1"),
+				"retried":              zaprec.Bool(true),
+			},
+		},
+		zaprec.Object{
+			"traceId": zaprec.String(second.TraceID().String()),
+			"spanId":  zaprec.String(second.SpanID().String()),
+		},
+	}
+
+	assert.Equal(t, "links", field.Key)
+	assert.Equal(t, zapcore.ArrayMarshalerType, field.Type)
+
+	am, ok := field.Interface.(zapcore.ArrayMarshaler)
+	require.True(t, ok, "field should have array marshaler set")
+
+	enc := zaprec.NewArrayEncoder()
+	err := am.MarshalLogArray(enc)
+
+	assert.NoError(t, err, "marshal log array should return nil error")
+	assert.Equal(t, expected, enc.Result(), "encoded links should match")
+}
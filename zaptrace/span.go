@@ -0,0 +1,200 @@
+package zaptrace
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SpanOptions configures the field constructed by SpanOptions.Span.
+type SpanOptions struct {
+	// Attributes lists the span attribute keys to be logged. No attribute is
+	// logged when it is empty.
+	Attributes []attribute.Key
+	// Links logs the span links with their traceId, spanId and attributes.
+	Links bool
+}
+
+func (opts SpanOptions) included(key attribute.Key) bool {
+	for _, k := range opts.Attributes {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+type attributeValueArrayMarshaler struct {
+	Value attribute.Value
+}
+
+func (m attributeValueArrayMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	switch m.Value.Type() {
+	case attribute.BOOLSLICE:
+		for _, v := range m.Value.AsBoolSlice() {
+			enc.AppendBool(v)
+		}
+
+	case attribute.INT64SLICE:
+		for _, v := range m.Value.AsInt64Slice() {
+			enc.AppendInt64(v)
+		}
+
+	case attribute.FLOAT64SLICE:
+		for _, v := range m.Value.AsFloat64Slice() {
+			enc.AppendFloat64(v)
+		}
+
+	case attribute.STRINGSLICE:
+		for _, v := range m.Value.AsStringSlice() {
+			enc.AppendString(v)
+		}
+	}
+
+	return nil
+}
+
+func addAttribute(enc zapcore.ObjectEncoder, kv attribute.KeyValue) error {
+	key := string(kv.Key)
+
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		enc.AddBool(key, kv.Value.AsBool())
+
+	case attribute.INT64:
+		enc.AddInt64(key, kv.Value.AsInt64())
+
+	case attribute.FLOAT64:
+		enc.AddFloat64(key, kv.Value.AsFloat64())
+
+	case attribute.STRING:
+		enc.AddString(key, kv.Value.AsString())
+
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE:
+		return enc.AddArray(key, attributeValueArrayMarshaler{
+			Value: kv.Value,
+		})
+	}
+
+	return nil
+}
+
+type attributesMarshaler []attribute.KeyValue
+
+func (m attributesMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, kv := range m {
+		if err := addAttribute(enc, kv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type spanStatusMarshaler struct {
+	Status sdktrace.Status
+}
+
+func (m spanStatusMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("code", m.Status.Code.String())
+
+	if m.Status.Description != "" {
+		enc.AddString("description", m.Status.Description)
+	}
+
+	return nil
+}
+
+type readOnlySpanMarshaler struct {
+	Options SpanOptions
+	Span    sdktrace.ReadOnlySpan
+}
+
+func (m readOnlySpanMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", m.Span.Name())
+	enc.AddString("kind", m.Span.SpanKind().String())
+	enc.AddTime("startTime", m.Span.StartTime())
+
+	if parent := m.Span.Parent(); parent.HasSpanID() {
+		enc.AddString("parentSpanId", parent.SpanID().String())
+		enc.AddBool("parentRemote", parent.IsRemote())
+	}
+
+	if err := enc.AddObject("status", spanStatusMarshaler{
+		Status: m.Span.Status(),
+	}); err != nil {
+		return err
+	}
+
+	if links := m.Span.Links(); m.Options.Links && len(links) > 0 {
+		if err := enc.AddArray("links", sdkLinksMarshalerOf(links)); err != nil {
+			return err
+		}
+	}
+
+	var attrs attributesMarshaler
+
+	for _, kv := range m.Span.Attributes() {
+		if m.Options.included(kv.Key) {
+			attrs = append(attrs, kv)
+		}
+	}
+
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	return enc.AddObject("attributes", attrs)
+}
+
+type spanMarshaler struct {
+	spanContextMarshaler
+	Options SpanOptions
+}
+
+func (m spanMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := m.spanContextMarshaler.MarshalLogObject(enc); err != nil {
+		return err
+	}
+
+	return spanDetailsMarshaler(m).MarshalLogObject(enc)
+}
+
+// spanDetailsMarshaler marshals the span field of spanMarshaler without the
+// traceId and spanId.
+type spanDetailsMarshaler spanMarshaler
+
+func (m spanDetailsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	ro, ok := m.Span.(sdktrace.ReadOnlySpan)
+	if !ok {
+		return nil
+	}
+
+	return enc.AddObject("span", readOnlySpanMarshaler{
+		Options: m.Options,
+		Span:    ro,
+	})
+}
+
+// Span constructs traceId and spanId field from a trace.Span. If the span is
+// created by the OpenTelemetry SDK, it also constructs a span field
+// containing the span name, kind, start time, parent span ID, whether the
+// parent is remote, status, the allowed attributes and optionally the links.
+func (opts SpanOptions) Span(span trace.Span) zap.Field {
+	return zap.Inline(spanMarshaler{
+		spanContextMarshaler: spanContextMarshaler{
+			SpanContext: span.SpanContext(),
+			Span:        span,
+		},
+		Options: opts,
+	})
+}
+
+// Span constructs traceId and spanId field from a trace.Span with the default
+// options. See SpanOptions.Span for details.
+func Span(span trace.Span) zap.Field {
+	return SpanOptions{}.Span(span)
+}
@@ -0,0 +1,180 @@
+package zaptrace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adzil/zapf/zaptrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSpan(t *testing.T) {
+	type Context struct {
+		Field   zap.Field
+		Expects map[string]interface{}
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	tracer := tp.Tracer("test")
+
+	for k, v := range map[string]func(t *testing.T, tc *Context){
+		"with non-sdk span": func(t *testing.T, tc *Context) {
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{2},
+			})
+			span := trace.SpanFromContext(trace.ContextWithSpanContext(context.Background(), spanCtx))
+
+			tc.Field = zaptrace.Span(span)
+
+			tc.Expects = map[string]interface{}{
+				"traceId": spanCtx.TraceID().String(),
+				"spanId":  spanCtx.SpanID().String(),
+			}
+		},
+
+		"with sdk root span": func(t *testing.T, tc *Context) {
+			_, span := tracer.Start(context.Background(), "root",
+				trace.WithAttributes(attribute.String("tenant", "acme")),
+			)
+			defer span.End()
+
+			tc.Field = zaptrace.Span(span)
+
+			ro, ok := span.(sdktrace.ReadOnlySpan)
+			require.True(t, ok, "sdk span must be a read only span")
+
+			tc.Expects = map[string]interface{}{
+				"traceId": span.SpanContext().TraceID().String(),
+				"spanId":  span.SpanContext().SpanID().String(),
+				"span": map[string]interface{}{
+					"name":      "root",
+					"kind":      "internal",
+					"startTime": ro.StartTime(),
+					"status": map[string]interface{}{
+						"code": "Unset",
+					},
+				},
+			}
+		},
+
+		"with sdk child span and allowed attributes": func(t *testing.T, tc *Context) {
+			ctx, parent := tracer.Start(context.Background(), "parent")
+			defer parent.End()
+
+			_, span := tracer.Start(ctx, "child",
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("tenant", "acme"),
+					attribute.Int64Slice("ids", []int64{1, 2}),
+					attribute.String("secret", "token"),
+				),
+			)
+			defer span.End()
+
+			span.SetStatus(codes.Error, "failed")
+
+			tc.Field = zaptrace.SpanOptions{
+				Attributes: []attribute.Key{"tenant", "ids"},
+			}.Span(span)
+
+			ro, ok := span.(sdktrace.ReadOnlySpan)
+			require.True(t, ok, "sdk span must be a read only span")
+
+			tc.Expects = map[string]interface{}{
+				"traceId": span.SpanContext().TraceID().String(),
+				"spanId":  span.SpanContext().SpanID().String(),
+				"span": map[string]interface{}{
+					"name":         "child",
+					"kind":         "server",
+					"startTime":    ro.StartTime(),
+					"parentSpanId": parent.SpanContext().SpanID().String(),
+					"parentRemote": false,
+					"status": map[string]interface{}{
+						"code":        "Error",
+						"description": "failed",
+					},
+					"attributes": map[string]interface{}{
+						"tenant": "acme",
+						"ids":    []interface{}{int64(1), int64(2)},
+					},
+				},
+			}
+		},
+
+		"with sdk span with remote parent and links": func(t *testing.T, tc *Context) {
+			remote := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{2},
+				TraceFlags: trace.FlagsSampled,
+				Remote:     true,
+			})
+			producer := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{3},
+				SpanID:  trace.SpanID{4},
+			})
+
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), remote)
+			_, span := tracer.Start(ctx, "consume",
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithLinks(trace.Link{
+					SpanContext: producer,
+					Attributes: []attribute.KeyValue{
+						attribute.String("messaging.message.id", "1"),
+					},
+				}),
+			)
+			defer span.End()
+
+			tc.Field = zaptrace.SpanOptions{
+				Links: true,
+			}.Span(span)
+
+			ro, ok := span.(sdktrace.ReadOnlySpan)
+			require.True(t, ok, "sdk span must be a read only span")
+
+			tc.Expects = map[string]interface{}{
+				"traceId": remote.TraceID().String(),
+				"spanId":  span.SpanContext().SpanID().String(),
+				"span": map[string]interface{}{
+					"name":         "consume",
+					"kind":         "consumer",
+					"startTime":    ro.StartTime(),
+					"parentSpanId": remote.SpanID().String(),
+					"parentRemote": true,
+					"status": map[string]interface{}{
+						"code": "Unset",
+					},
+					"links": []interface{}{
+						map[string]interface{}{
+							"traceId": producer.TraceID().String(),
+							"spanId":  producer.SpanID().String(),
+							"attributes": map[string]interface{}{
+								"messaging.message.id": "1",
+							},
+						},
+					},
+				},
+			}
+		},
+	} {
+		t.Run(k, func(t *testing.T) {
+			tc := &Context{}
+			v(t, tc)
+
+			assert.Equal(t, zapcore.InlineMarshalerType, tc.Field.Type)
+
+			enc := zapcore.NewMapObjectEncoder()
+			tc.Field.AddTo(enc)
+
+			assert.Equal(t, tc.Expects, enc.Fields, "encoded span should match")
+		})
+	}
+}